@@ -0,0 +1,90 @@
+// Package gdoctableapp (backend.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the backend for Docs API and Drive API.
+package gdoctableapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	docs "google.golang.org/api/docs/v1"
+	drive "google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+type (
+	// Backend : Interface for the requests to Docs API and Drive API.
+	// When Backend is not set by SetBackend, Docs API and Drive API are used with the client given to Do.
+	// FakeBackend can be used for testing the scripts without Google account.
+	Backend interface {
+		GetDocument(ctx context.Context, documentID string, fields ...googleapi.Field) (*docs.Document, error)
+		BatchUpdate(ctx context.Context, documentID string, req *docs.BatchUpdateDocumentRequest) (*docs.BatchUpdateDocumentResponse, error)
+		CreateFile(ctx context.Context, file *drive.File, media io.Reader) (*drive.File, error)
+		CreatePermission(ctx context.Context, fileID string, permission *drive.Permission) (*drive.Permission, error)
+		DeleteFile(ctx context.Context, fileID string) error
+	}
+
+	// apiBackend : Backend using Docs API and Drive API.
+	apiBackend struct {
+		client   *http.Client
+		srv      *docs.Service
+		srvDrive *drive.Service
+	}
+)
+
+// newAPIBackend : Create Backend using Docs API and Drive API.
+func newAPIBackend(client *http.Client) (*apiBackend, error) {
+	srv, err := docs.New(client)
+	if err != nil {
+		return nil, err
+	}
+	return &apiBackend{client: client, srv: srv}, nil
+}
+
+// GetDocument : Retrieve Document with Documents.Get.
+func (b *apiBackend) GetDocument(ctx context.Context, documentID string, fields ...googleapi.Field) (*docs.Document, error) {
+	return b.srv.Documents.Get(documentID).Fields(fields...).Context(ctx).Do()
+}
+
+// BatchUpdate : Request Documents.BatchUpdate.
+func (b *apiBackend) BatchUpdate(ctx context.Context, documentID string, req *docs.BatchUpdateDocumentRequest) (*docs.BatchUpdateDocumentResponse, error) {
+	return b.srv.Documents.BatchUpdate(documentID, req).Context(ctx).Do()
+}
+
+// CreateFile : Upload a file with Files.Create.
+func (b *apiBackend) CreateFile(ctx context.Context, file *drive.File, media io.Reader) (*drive.File, error) {
+	if err := b.getSrvForDrive(); err != nil {
+		return nil, err
+	}
+	return b.srvDrive.Files.Create(file).Media(media).Fields("id,webContentLink").Context(ctx).Do()
+}
+
+// CreatePermission : Create a permission with Permissions.Create.
+func (b *apiBackend) CreatePermission(ctx context.Context, fileID string, permission *drive.Permission) (*drive.Permission, error) {
+	if err := b.getSrvForDrive(); err != nil {
+		return nil, err
+	}
+	return b.srvDrive.Permissions.Create(fileID, permission).Context(ctx).Do()
+}
+
+// DeleteFile : Delete a file with Files.Delete.
+func (b *apiBackend) DeleteFile(ctx context.Context, fileID string) error {
+	if err := b.getSrvForDrive(); err != nil {
+		return err
+	}
+	return b.srvDrive.Files.Delete(fileID).Context(ctx).Do()
+}
+
+// getSrvForDrive : Get service for using Drive API.
+func (b *apiBackend) getSrvForDrive() error {
+	if b.srvDrive != nil {
+		return nil
+	}
+	srv, err := drive.New(b.client)
+	if err != nil {
+		return err
+	}
+	b.srvDrive = srv
+	return nil
+}
@@ -0,0 +1,147 @@
+// Package gdoctableapp (cells.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the methods for retrieving the rich contents of cells.
+package gdoctableapp
+
+import (
+	"fmt"
+	"strings"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// Types of CellElement.
+const (
+	CellElementText         = "text"
+	CellElementInlineObject = "inlineObject"
+	CellElementTable        = "table"
+	CellElementUnsupported  = "unsupported"
+)
+
+// getCells : Retrieve the rich contents of the cells of the table.
+func (o *obj) getCells() [][]Cell {
+	return o.createCells(o.docTable.Table)
+}
+
+// createCells : Create the cells from table. The nested tables are converted recursively.
+func (o *obj) createCells(table *docs.Table) [][]Cell {
+	res := [][]Cell{}
+	for i, row := range table.TableRows {
+		cells := []Cell{}
+		for j, c := range row.TableCells {
+			cell := Cell{Row: int64(i), Column: int64(j), Elements: []CellElement{}}
+			var text strings.Builder
+			for _, e := range c.Content {
+				switch {
+				case e.Paragraph != nil:
+					for _, pe := range e.Paragraph.Elements {
+						ce := o.createCellElement(pe)
+						text.WriteString(ce.Text)
+						cell.Elements = append(cell.Elements, ce)
+					}
+				case e.Table != nil:
+					cell.Elements = append(cell.Elements, CellElement{
+						Type:       CellElementTable,
+						StartIndex: e.StartIndex,
+						EndIndex:   e.EndIndex,
+						Table:      o.createCells(e.Table),
+					})
+				default:
+					cell.Elements = append(cell.Elements, CellElement{
+						Type:       CellElementUnsupported,
+						StartIndex: e.StartIndex,
+						EndIndex:   e.EndIndex,
+					})
+				}
+			}
+			cell.Text = strings.TrimSuffix(text.String(), "\n")
+			cells = append(cells, cell)
+		}
+		res = append(res, cells)
+	}
+	return res
+}
+
+// createCellElement : Create CellElement from the element of a paragraph.
+func (o *obj) createCellElement(pe *docs.ParagraphElement) CellElement {
+	ce := CellElement{
+		Type:       CellElementUnsupported,
+		StartIndex: pe.StartIndex,
+		EndIndex:   pe.EndIndex,
+	}
+	switch {
+	case pe.TextRun != nil:
+		ce.Type = CellElementText
+		ce.Text = pe.TextRun.Content
+		ce.Style = textStyleFromDocs(pe.TextRun.TextStyle)
+	case pe.InlineObjectElement != nil:
+		ce.Type = CellElementInlineObject
+		ce.Style = textStyleFromDocs(pe.InlineObjectElement.TextStyle)
+		ce.InlineObject = o.createInlineObject(pe.InlineObjectElement.InlineObjectId)
+	}
+	return ce
+}
+
+// createInlineObject : Create InlineObject from the inline object of Document.
+// When the inline object is not included in Document, only the ID is set.
+func (o *obj) createInlineObject(id string) *InlineObject {
+	res := &InlineObject{ObjectID: id}
+	io, ok := o.inlineObjects[id]
+	if !ok || io.InlineObjectProperties == nil || io.InlineObjectProperties.EmbeddedObject == nil {
+		return res
+	}
+	eo := io.InlineObjectProperties.EmbeddedObject
+	res.Title = eo.Title
+	res.Description = eo.Description
+	if eo.ImageProperties != nil {
+		res.ContentURI = eo.ImageProperties.ContentUri
+		res.SourceURI = eo.ImageProperties.SourceUri
+	}
+	if eo.Size != nil {
+		if eo.Size.Width != nil {
+			res.Width = eo.Size.Width.Magnitude
+		}
+		if eo.Size.Height != nil {
+			res.Height = eo.Size.Height.Magnitude
+		}
+	}
+	return res
+}
+
+// textStyleFromDocs : Convert the style of Docs API to TextStyle. Only the fields which are set are converted.
+// When no fields are set, nil is returned.
+func textStyleFromDocs(ts *docs.TextStyle) *TextStyle {
+	if ts == nil {
+		return nil
+	}
+	s := &TextStyle{}
+	if ts.Bold {
+		s.Bold = Bool(true)
+	}
+	if ts.Italic {
+		s.Italic = Bool(true)
+	}
+	if ts.Underline {
+		s.Underline = Bool(true)
+	}
+	if ts.Strikethrough {
+		s.Strikethrough = Bool(true)
+	}
+	if ts.WeightedFontFamily != nil {
+		s.FontFamily = ts.WeightedFontFamily.FontFamily
+	}
+	if ts.FontSize != nil {
+		s.FontSize = ts.FontSize.Magnitude
+	}
+	if c := ts.ForegroundColor; c != nil && c.Color != nil && c.Color.RgbColor != nil {
+		rgb := c.Color.RgbColor
+		s.ForegroundColor = fmt.Sprintf("#%02x%02x%02x", int64(rgb.Red*255+0.5), int64(rgb.Green*255+0.5), int64(rgb.Blue*255+0.5))
+	}
+	if ts.Link != nil {
+		s.Link = ts.Link.Url
+	}
+	if *s == (TextStyle{}) {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,102 @@
+package gdoctableapp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// newCellsTestTable : Create a table of 2 rows and 2 columns for retrieving the rich contents of cells.
+// Cell (0, 0) has the styled run and the normal run, cell (0, 1) has an inline image between the texts,
+// and cell (1, 0) has a nested table of "n".
+func newCellsTestTable(t *testing.T) *FakeBackend {
+	t.Helper()
+	style := &TextStyle{Bold: Bool(true), FontSize: 14, ForegroundColor: "#ff0000", Link: "https://example.com/"}
+	f := newTestTable(t, [][]interface{}{{StyledValue{Value: "ab", Style: style}, "xy"}, {"", "d"}})
+	cell := func(i, j int) *docs.TableCell {
+		return testTable(t, f).TableRows[i].TableCells[j]
+	}
+	if err := fakeApply(f, insertText(cell(0, 0).Content[0].StartIndex+2, "cd")); err != nil {
+		t.Fatal(err)
+	}
+	image := &docs.Request{InsertInlineImage: &docs.InsertInlineImageRequest{
+		Location:   &docs.Location{Index: cell(0, 1).Content[0].StartIndex + 1},
+		Uri:        "https://example.com/image.png",
+		ObjectSize: &docs.Size{Width: &docs.Dimension{Magnitude: 100, Unit: "PT"}, Height: &docs.Dimension{Magnitude: 50, Unit: "PT"}},
+	}}
+	if err := fakeApply(f, image); err != nil {
+		t.Fatal(err)
+	}
+	nested := &docs.Request{InsertTable: &docs.InsertTableRequest{Rows: 1, Columns: 1, Location: &docs.Location{Index: cell(1, 0).Content[0].StartIndex}}}
+	if err := fakeApply(f, nested); err != nil {
+		t.Fatal(err)
+	}
+	if err := fakeApply(f, insertText(cell(1, 0).Content[1].Table.TableRows[0].TableCells[0].Content[0].StartIndex, "n")); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestGetCells(t *testing.T) {
+	f := newCellsTestTable(t)
+	res, err := New().Docs("doc").SetBackend(f).GetCells().Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := testTable(t, f)
+	cell := func(i, j int) *docs.TableCell {
+		return table.TableRows[i].TableCells[j]
+	}
+	element := func(i, j, k, l int) (int64, int64) {
+		pe := cell(i, j).Content[k].Paragraph.Elements[l]
+		return pe.StartIndex, pe.EndIndex
+	}
+	text := func(i, j, k, l int, s string, style *TextStyle) CellElement {
+		start, end := element(i, j, k, l)
+		return CellElement{Type: CellElementText, StartIndex: start, EndIndex: end, Text: s, Style: style}
+	}
+	imageStart, imageEnd := element(0, 1, 0, 1)
+	nested := cell(1, 0).Content[1]
+	nestedCell := nested.Table.TableRows[0].TableCells[0].Content[0].Paragraph.Elements[0]
+	want := [][]Cell{
+		{
+			{Row: 0, Column: 0, Text: "abcd", Elements: []CellElement{
+				text(0, 0, 0, 0, "ab", &TextStyle{Bold: Bool(true), FontSize: 14, ForegroundColor: "#ff0000", Link: "https://example.com/"}),
+				text(0, 0, 0, 1, "cd\n", nil),
+			}},
+			{Row: 0, Column: 1, Text: "xy", Elements: []CellElement{
+				text(0, 1, 0, 0, "x", nil),
+				{Type: CellElementInlineObject, StartIndex: imageStart, EndIndex: imageEnd, InlineObject: &InlineObject{
+					ObjectID:   "kix.fake1",
+					ContentURI: "https://example.com/image.png",
+					SourceURI:  "https://example.com/image.png",
+					Width:      100,
+					Height:     50,
+				}},
+				text(0, 1, 0, 2, "y\n", nil),
+			}},
+		},
+		{
+			// The texts of the nested table are not included in Text of the cell.
+			{Row: 1, Column: 0, Text: "\n", Elements: []CellElement{
+				text(1, 0, 0, 0, "\n", nil),
+				{Type: CellElementTable, StartIndex: nested.StartIndex, EndIndex: nested.EndIndex, Table: [][]Cell{{
+					{Row: 0, Column: 0, Text: "n", Elements: []CellElement{
+						{Type: CellElementText, StartIndex: nestedCell.StartIndex, EndIndex: nestedCell.EndIndex, Text: "n\n"},
+					}},
+				}}},
+				text(1, 0, 2, 0, "\n", nil),
+			}},
+			{Row: 1, Column: 1, Text: "d", Elements: []CellElement{text(1, 1, 0, 0, "d\n", nil)}},
+		},
+	}
+	if !reflect.DeepEqual(res.Cells, want) {
+		got, _ := json.Marshal(res.Cells)
+		w, _ := json.Marshal(want)
+		t.Errorf("cells = %s, want %s", got, w)
+	}
+	// GetValues uses the placeholders for the inline object and the nested table.
+	assertValues(t, testValues(t, f), [][]string{{"abcd", "x[INLINE OBJECT]y"}, {"\n[TABLE]", "d"}})
+}
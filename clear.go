@@ -0,0 +1,61 @@
+// Package gdoctableapp (clear.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the methods for clearing cells.
+package gdoctableapp
+
+import (
+	"fmt"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// clearTable : Clear all cells of the table.
+func (o *obj) clearTable() error {
+	table := o.docTable.Table
+	return o.clearCells(0, 0, table.Rows, table.Columns)
+}
+
+// clearRange : Clear the cells of ClearRangeP.
+func (o *obj) clearRange() error {
+	r := o.params.ClearRangeP
+	table := o.docTable.Table
+	if r.StartRowIndex < 0 || r.StartColumnIndex < 0 || r.EndRowIndex > table.Rows || r.EndColumnIndex > table.Columns {
+		return fmt.Errorf("Range for clearing is outside of the table")
+	}
+	if r.StartRowIndex >= r.EndRowIndex || r.StartColumnIndex >= r.EndColumnIndex {
+		return fmt.Errorf("Range for clearing is empty. The end indexes are not included in the range")
+	}
+	return o.clearCells(r.StartRowIndex, r.StartColumnIndex, r.EndRowIndex, r.EndColumnIndex)
+}
+
+// clearCells : Delete all contents of the cells from (startRow, startCol) to (endRow, endCol) except for the last newline of each cell.
+// The texts, inline objects and nested tables are deleted, and the table grid is kept. All requests are run by one batchUpdate.
+func (o *obj) clearCells(startRow, startCol, endRow, endCol int64) error {
+	rows := o.docTable.Table.TableRows
+	br := &docs.BatchUpdateDocumentRequest{}
+	for i := endRow - 1; i >= startRow; i-- {
+		cells := rows[i].TableCells
+		for j := endCol - 1; j >= startCol; j-- {
+			if j >= int64(len(cells)) {
+				continue
+			}
+			content := cells[j].Content
+			if len(content) == 0 {
+				continue
+			}
+			si := content[0].StartIndex
+			ei := content[len(content)-1].EndIndex - 1
+			if si < ei {
+				br.Requests = append(br.Requests, createDeleteContentRangeRequest(si, ei))
+			}
+		}
+	}
+	if len(br.Requests) == 0 {
+		return nil
+	}
+	o.requestBody = br
+	if err := o.documentbatchUpdate(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package gdoctableapp
+
+import (
+	"reflect"
+	"testing"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// newClearTestTable : Create a table of 2 rows and 3 columns for clearing cells.
+// Cell (0, 1) has 2 paragraphs, cell (0, 2) has an inline image after the text, and cell (1, 1) has a nested table.
+func newClearTestTable(t *testing.T) *FakeBackend {
+	t.Helper()
+	f := newTestTable(t, [][]interface{}{{"a", "line1\nline2", "b"}, {"c", "", ""}})
+	cell := func(i, j int) *docs.TableCell {
+		return testTable(t, f).TableRows[i].TableCells[j]
+	}
+	nested := &docs.Request{InsertTable: &docs.InsertTableRequest{Rows: 1, Columns: 2, Location: &docs.Location{Index: cell(1, 1).Content[0].StartIndex}}}
+	if err := fakeApply(f, nested); err != nil {
+		t.Fatal(err)
+	}
+	image := &docs.Request{InsertInlineImage: &docs.InsertInlineImageRequest{Location: &docs.Location{Index: cell(0, 2).Content[0].StartIndex + 1}, Uri: "https://example.com/image.png"}}
+	if err := fakeApply(f, image); err != nil {
+		t.Fatal(err)
+	}
+	if len(cell(1, 1).Content) != 3 || cell(1, 1).Content[1].Table == nil {
+		t.Fatalf("nested table was not inserted: %s", fakeLayout(f.Document("doc").Body.Content))
+	}
+	return f
+}
+
+// clearedRanges : Return the ranges from the start of the first element to the end of the last element except for the last newline
+// of the cells in order of the cells.
+func clearedRanges(table *docs.Table, cells [][2]int) [][2]int64 {
+	var res [][2]int64
+	for _, c := range cells {
+		content := table.TableRows[c[0]].TableCells[c[1]].Content
+		res = append(res, [2]int64{content[0].StartIndex, content[len(content)-1].EndIndex - 1})
+	}
+	return res
+}
+
+// deletedRanges : Return the ranges of DeleteContentRangeRequests.
+func deletedRanges(requests []*docs.Request) [][2]int64 {
+	var res [][2]int64
+	for _, r := range requests {
+		res = append(res, [2]int64{r.DeleteContentRange.Range.StartIndex, r.DeleteContentRange.Range.EndIndex})
+	}
+	return res
+}
+
+// assertClearedCells : Check that the cells of the table are empty paragraphs except for the cells in kept.
+func assertClearedCells(t *testing.T, table *docs.Table, kept map[[2]int]bool) {
+	t.Helper()
+	for i, row := range table.TableRows {
+		if len(row.TableCells) != 3 {
+			t.Errorf("row %d has %d cells, want 3", i, len(row.TableCells))
+		}
+		for j, cell := range row.TableCells {
+			if kept[[2]int{i, j}] {
+				continue
+			}
+			if len(cell.Content) != 1 || cell.Content[0].Paragraph == nil || fakeCellText(cell) != "" {
+				t.Errorf("cell (%d, %d) was not cleared: %s", i, j, fakeLayout(cell.Content))
+			}
+		}
+	}
+}
+
+func TestClearRange(t *testing.T) {
+	f := newClearTestTable(t)
+	b := &hookBackend{FakeBackend: f}
+	// The cells are deleted from the last cell, and the empty cell (1, 2) is skipped.
+	want := clearedRanges(testTable(t, f), [][2]int{{1, 1}, {0, 2}, {0, 1}})
+	if _, err := New().Docs("doc").SetBackend(b).ClearRange(0, 1, 2, 3).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.requests) != 1 {
+		t.Fatalf("batchUpdate was requested %d times, want 1", len(b.requests))
+	}
+	if got := deletedRanges(b.requests[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("deleted ranges = %v, want %v", got, want)
+	}
+	assertClearedCells(t, testTable(t, f), map[[2]int]bool{{0, 0}: true, {1, 0}: true})
+	assertValues(t, testValues(t, f), [][]string{{"a", "", ""}, {"c", "", ""}})
+}
+
+func TestClearTable(t *testing.T) {
+	f := newClearTestTable(t)
+	b := &hookBackend{FakeBackend: f}
+	want := clearedRanges(testTable(t, f), [][2]int{{1, 1}, {1, 0}, {0, 2}, {0, 1}, {0, 0}})
+	if _, err := New().Docs("doc").SetBackend(b).ClearTable().Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.requests) != 1 {
+		t.Fatalf("batchUpdate was requested %d times, want 1", len(b.requests))
+	}
+	if got := deletedRanges(b.requests[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("deleted ranges = %v, want %v", got, want)
+	}
+	assertClearedCells(t, testTable(t, f), nil)
+
+	// The empty table is not changed.
+	b = &hookBackend{FakeBackend: f}
+	if _, err := New().Docs("doc").SetBackend(b).ClearTable().Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	if b.batches != 0 {
+		t.Errorf("batchUpdate was requested %d times, want 0", b.batches)
+	}
+}
+
+func TestClearRangeInvalidRanges(t *testing.T) {
+	f := newClearTestTable(t)
+	b := &hookBackend{FakeBackend: f}
+	for _, r := range [][4]int64{{-1, 0, 1, 1}, {0, 0, 3, 1}, {0, 0, 1, 4}, {1, 0, 1, 3}, {0, 2, 2, 1}} {
+		if _, err := New().Docs("doc").SetBackend(b).ClearRange(r[0], r[1], r[2], r[3]).Do(nil); err == nil {
+			t.Errorf("no error for %v", r)
+		}
+	}
+	if b.batches != 0 {
+		t.Errorf("batchUpdate was requested %d times, want 0", b.batches)
+	}
+}
@@ -0,0 +1,135 @@
+// Package gdoctableapp (errors.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes errors.
+package gdoctableapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+// errMaybeApplied : Error returned when the requests of batchUpdate might be applied by the attempt which failed with the server error.
+var errMaybeApplied = errors.New("Requests might be applied by the failed attempt, because the revision ID of Document was changed")
+
+type (
+	// CanceledError : Error returned when the context is canceled or its deadline is exceeded during the request to APIs.
+	// Err is context.Canceled or context.DeadlineExceeded. So errors.Is(err, context.Canceled) can be used.
+	CanceledError struct {
+		Op  string // Name of the request which was stopped. e.g. "Documents.BatchUpdate"
+		Err error
+	}
+
+	// ConflictError : Error returned when the Document was modified after it was retrieved.
+	// The requests are not applied, because the revision ID of the Document is different from RequiredRevisionId.
+	ConflictError struct {
+		RevisionID string // Revision ID used as RequiredRevisionId.
+		Err        error
+	}
+
+	// ValuesError : Error returned when the values of cells cannot be converted by GetValuesInto and UnmarshalValues.
+	ValuesError struct {
+		Errors []CellError
+	}
+
+	// APIError : Error returned from Docs API and Drive API.
+	APIError struct {
+		Op   string // Name of the request which failed. e.g. "Documents.BatchUpdate"
+		Code int    // HTTP status code. When the status code cannot be retrieved, this is 0.
+		Err  error
+	}
+)
+
+// Error : Error message of CanceledError.
+func (e *CanceledError) Error() string {
+	return fmt.Sprintf("%s was canceled: %v", e.Op, e.Err)
+}
+
+// Unwrap : Return the error from the context.
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+// Error : Error message of ConflictError.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("Document was modified after the revision of %s was retrieved: %v", e.RevisionID, e.Err)
+}
+
+// Unwrap : Return the error from the client library.
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Error : Error message of CellError.
+func (e CellError) Error() string {
+	return fmt.Sprintf("row %d, column %d (%s): %q: %v", e.Row, e.Column, e.Field, e.Value, e.Err)
+}
+
+// Unwrap : Return the error of the conversion.
+func (e CellError) Unwrap() error {
+	return e.Err
+}
+
+// Error : Error message of ValuesError.
+func (e *ValuesError) Error() string {
+	var msgs []string
+	for i, f := range e.Errors {
+		if i == 3 {
+			msgs = append(msgs, "...")
+			break
+		}
+		msgs = append(msgs, f.Error())
+	}
+	return fmt.Sprintf("Values of %d cells cannot be converted: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Error : Error message of APIError.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+// Unwrap : Return the error from the client library.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// wrapError : Wrap the error returned by the request of op.
+func (o *obj) wrapError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctxErr := o.ctx.Err(); ctxErr != nil {
+		return &CanceledError{Op: op, Err: ctxErr}
+	}
+	e := &APIError{Op: op, Err: err}
+	var gErr *googleapi.Error
+	if errors.As(err, &gErr) {
+		e.Code = gErr.Code
+	}
+	return e
+}
+
+// isConflict : Check whether the error is due to RequiredRevisionId of WriteControl.
+// When RequiredRevisionId is not the latest revision ID, Docs API returns 400 with the status of FAILED_PRECONDITION.
+// The status is checked with the reasons of the errors and the body of the response. The message is not used.
+func isConflict(err error) bool {
+	var e *googleapi.Error
+	if !errors.As(err, &e) || e.Code != http.StatusBadRequest {
+		return false
+	}
+	for _, item := range e.Errors {
+		if item.Reason == "failedPrecondition" || item.Reason == "FAILED_PRECONDITION" {
+			return true
+		}
+	}
+	var body struct {
+		Error struct {
+			Status string `json:"status"`
+		} `json:"error"`
+	}
+	return json.Unmarshal([]byte(e.Body), &body) == nil && body.Error.Status == "FAILED_PRECONDITION"
+}
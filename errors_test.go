@@ -0,0 +1,62 @@
+package gdoctableapp
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsConflict(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"status of body", fakeError(http.StatusBadRequest, "FAILED_PRECONDITION", "The required revision ID 1 does not match the latest revision 2."), true},
+		{"reason of errors", &googleapi.Error{Code: http.StatusBadRequest, Errors: []googleapi.ErrorItem{{Reason: "failedPrecondition"}}}, true},
+		{"wrapped", &APIError{Op: "Documents.BatchUpdate", Err: fakeError(http.StatusBadRequest, "FAILED_PRECONDITION", "")}, true},
+		{"message including revision", fakeError(http.StatusBadRequest, "INVALID_ARGUMENT", "Invalid revision of the image."), false},
+		{"message without body", &googleapi.Error{Code: http.StatusBadRequest, Message: "The required revision ID 1 does not match the latest revision 2."}, false},
+		{"other status code", fakeError(http.StatusConflict, "FAILED_PRECONDITION", ""), false},
+		{"not API error", errors.New("revision"), false},
+	} {
+		if got := isConflict(c.err); got != c.want {
+			t.Errorf("%s: isConflict(%v) = %v, want %v", c.name, c.err, got, c.want)
+		}
+	}
+}
+
+func TestBadRequestMentioningRevisionIsNotConflict(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}})
+	b := &hookBackend{FakeBackend: f, before: func(n int) error {
+		return fakeError(http.StatusBadRequest, "INVALID_ARGUMENT", "Invalid revision of the request.")
+	}}
+	_, err := New().Docs("doc").SetBackend(b).SetValuesBy2DArray([][]interface{}{{"c"}}).RetryOnConflict(3).Do(nil)
+	var conflict *ConflictError
+	var apiErr *APIError
+	if errors.As(err, &conflict) || !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest {
+		t.Fatalf("err = %v, want APIError of 400", err)
+	}
+	if b.batches != 1 {
+		t.Errorf("batchUpdate was requested %d times, want 1", b.batches)
+	}
+}
+
+func TestFailedPreconditionWithoutRevisionIsNotConflict(t *testing.T) {
+	f := NewFakeBackend()
+	f.NewDocument("doc")
+	// CreateTable with Index requests batchUpdate without RequiredRevisionId.
+	b := &hookBackend{FakeBackend: f, before: func(n int) error {
+		return fakeError(http.StatusBadRequest, "FAILED_PRECONDITION", "Precondition check failed.")
+	}}
+	_, err := New().Docs("doc").SetBackend(b).CreateTable(&CreateTableRequest{Rows: 1, Columns: 1, Index: 1}).RetryOnConflict(3).Do(nil)
+	var conflict *ConflictError
+	if err == nil || errors.As(err, &conflict) {
+		t.Fatalf("err = %v, want APIError", err)
+	}
+	if b.batches != 1 {
+		t.Errorf("batchUpdate was requested %d times, want 1", b.batches)
+	}
+}
@@ -0,0 +1,1003 @@
+// Package gdoctableapp (fake.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the in-memory fake of Docs API and Drive API.
+package gdoctableapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	docs "google.golang.org/api/docs/v1"
+	drive "google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// FakeBackend : In-memory Backend for testing the scripts without Google account.
+// The requests of batchUpdate are applied to the Documents in memory, and the indexes are recalculated in UTF-16 code units.
+// The supported requests are InsertTable, InsertText, DeleteContentRange, InsertInlineImage,
+// InsertTableRow, InsertTableColumn, DeleteTableRow, DeleteTableColumn, UpdateTextStyle, UpdateTableCellStyle,
+// UpdateTableColumnProperties, PinTableHeaderRows, MergeTableCells and UnmergeTableCells.
+//
+// sample:
+//
+//	fake := gdoctableapp.NewFakeBackend()
+//	fake.NewDocument("sampleDocumentID")
+//	res, err := gdoctableapp.New().Docs("sampleDocumentID").SetBackend(fake).CreateTable(obj).Do(nil)
+type FakeBackend struct {
+	mu          sync.Mutex
+	documents   map[string]*docs.Document
+	revisions   map[string]int
+	files       map[string]*drive.File
+	fileCount   int
+	objectCount int
+}
+
+// NewFakeBackend : Create FakeBackend.
+func NewFakeBackend() *FakeBackend {
+	return &FakeBackend{
+		documents: map[string]*docs.Document{},
+		revisions: map[string]int{},
+		files:     map[string]*drive.File{},
+	}
+}
+
+// NewDocument : Create an empty Document of documentID.
+func (f *FakeBackend) NewDocument(documentID string) {
+	f.SetDocument(documentID, &docs.Document{})
+}
+
+// SetDocument : Set Document of documentID. The indexes of the Document are recalculated from the content.
+// When the body is empty, an empty paragraph is created.
+func (f *FakeBackend) SetDocument(documentID string, doc *docs.Document) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d := fakeCopyDocument(doc)
+	d.DocumentId = documentID
+	if d.Body == nil {
+		d.Body = &docs.Body{}
+	}
+	if len(d.Body.Content) == 0 {
+		d.Body.Content = []*docs.StructuralElement{
+			{SectionBreak: &docs.SectionBreak{}},
+			fakeNewParagraph(),
+		}
+	}
+	fakeReindexDocument(d)
+	f.revisions[documentID]++
+	d.RevisionId = strconv.Itoa(f.revisions[documentID])
+	f.documents[documentID] = d
+}
+
+// Document : Return a copy of Document of documentID. When the Document is not found, nil is returned.
+func (f *FakeBackend) Document(documentID string) *docs.Document {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, ok := f.documents[documentID]
+	if !ok {
+		return nil
+	}
+	return fakeCopyDocument(d)
+}
+
+// FileIDs : Return the IDs of the files which are not deleted.
+func (f *FakeBackend) FileIDs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ids []string
+	for id := range f.files {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// GetDocument : Return a copy of Document. fields are ignored.
+func (f *FakeBackend) GetDocument(ctx context.Context, documentID string, fields ...googleapi.Field) (*docs.Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d := f.Document(documentID)
+	if d == nil {
+		return nil, fakeNotFound()
+	}
+	return d, nil
+}
+
+// BatchUpdate : Apply the requests to Document. When one of the requests fails, no requests are applied.
+func (f *FakeBackend) BatchUpdate(ctx context.Context, documentID string, req *docs.BatchUpdateDocumentRequest) (*docs.BatchUpdateDocumentResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	doc, ok := f.documents[documentID]
+	if !ok {
+		return nil, fakeNotFound()
+	}
+	if req.WriteControl != nil && req.WriteControl.RequiredRevisionId != "" && req.WriteControl.RequiredRevisionId != doc.RevisionId {
+		return nil, fakeError(http.StatusBadRequest, "FAILED_PRECONDITION", fmt.Sprintf("The required revision ID %s does not match the latest revision %s.", req.WriteControl.RequiredRevisionId, doc.RevisionId))
+	}
+	d := fakeCopyDocument(doc)
+	res := &docs.BatchUpdateDocumentResponse{DocumentId: documentID}
+	for i, r := range req.Requests {
+		if err := f.apply(d, r); err != nil {
+			return nil, fakeError(http.StatusBadRequest, "INVALID_ARGUMENT", fmt.Sprintf("Invalid requests[%d]: %v", i, err))
+		}
+		fakeReindexDocument(d)
+		res.Replies = append(res.Replies, &docs.Response{})
+	}
+	f.revisions[documentID]++
+	d.RevisionId = strconv.Itoa(f.revisions[documentID])
+	f.documents[documentID] = d
+	res.WriteControl = &docs.WriteControl{RequiredRevisionId: d.RevisionId}
+	return res, nil
+}
+
+// CreateFile : Store the file in memory.
+func (f *FakeBackend) CreateFile(ctx context.Context, file *drive.File, media io.Reader) (*drive.File, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadAll(media); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fileCount++
+	id := fmt.Sprintf("fakeFile%d", f.fileCount)
+	res := &drive.File{
+		Id:             id,
+		Name:           file.Name,
+		WebContentLink: "https://drive.google.com/uc?id=" + id + "&export=download",
+	}
+	f.files[id] = res
+	c := *res
+	return &c, nil
+}
+
+// CreatePermission : Return the permission. The permission is not checked by FakeBackend.
+func (f *FakeBackend) CreatePermission(ctx context.Context, fileID string, permission *drive.Permission) (*drive.Permission, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.files[fileID]; !ok {
+		return nil, fakeNotFound()
+	}
+	return &drive.Permission{
+		Id:   "fakePermission",
+		Type: permission.Type,
+		Role: permission.Role,
+	}, nil
+}
+
+// DeleteFile : Delete the file from memory.
+func (f *FakeBackend) DeleteFile(ctx context.Context, fileID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.files[fileID]; !ok {
+		return fakeNotFound()
+	}
+	delete(f.files, fileID)
+	return nil
+}
+
+// apply : Apply a request to Document.
+func (f *FakeBackend) apply(d *docs.Document, r *docs.Request) error {
+	switch {
+	case r.InsertText != nil:
+		index, err := fakeLocation(d, r.InsertText.Location, r.InsertText.EndOfSegmentLocation)
+		if err != nil {
+			return err
+		}
+		if r.InsertText.Text == "" {
+			return fmt.Errorf("text is empty")
+		}
+		return fakeInsertElement(d, index, &docs.ParagraphElement{TextRun: &docs.TextRun{Content: r.InsertText.Text}})
+	case r.InsertInlineImage != nil:
+		index, err := fakeLocation(d, r.InsertInlineImage.Location, r.InsertInlineImage.EndOfSegmentLocation)
+		if err != nil {
+			return err
+		}
+		f.objectCount++
+		id := fmt.Sprintf("kix.fake%d", f.objectCount)
+		if err := fakeInsertElement(d, index, &docs.ParagraphElement{InlineObjectElement: &docs.InlineObjectElement{InlineObjectId: id}}); err != nil {
+			return err
+		}
+		if d.InlineObjects == nil {
+			d.InlineObjects = map[string]docs.InlineObject{}
+		}
+		d.InlineObjects[id] = docs.InlineObject{
+			ObjectId: id,
+			InlineObjectProperties: &docs.InlineObjectProperties{
+				EmbeddedObject: &docs.EmbeddedObject{
+					Size: r.InsertInlineImage.ObjectSize,
+					ImageProperties: &docs.ImageProperties{
+						ContentUri: r.InsertInlineImage.Uri,
+						SourceUri:  r.InsertInlineImage.Uri,
+					},
+				},
+			},
+		}
+		return nil
+	case r.DeleteContentRange != nil:
+		rng := r.DeleteContentRange.Range
+		if rng == nil || rng.StartIndex >= rng.EndIndex {
+			return fmt.Errorf("range is empty")
+		}
+		content, err := fakeDeleteRange(d.Body.Content, rng.StartIndex, rng.EndIndex)
+		if err != nil {
+			return err
+		}
+		d.Body.Content = content
+		return nil
+	case r.InsertTable != nil:
+		if r.InsertTable.Rows < 1 || r.InsertTable.Columns < 1 {
+			return fmt.Errorf("rows and columns must be 1 or more")
+		}
+		index, err := fakeLocation(d, r.InsertTable.Location, r.InsertTable.EndOfSegmentLocation)
+		if err != nil {
+			return err
+		}
+		if err := fakeInsertElement(d, index, &docs.ParagraphElement{TextRun: &docs.TextRun{Content: "\n"}}); err != nil {
+			return err
+		}
+		fakeReindexDocument(d)
+		content, pos := fakeFindContainer(&d.Body.Content, index+1)
+		if content == nil {
+			return fmt.Errorf("index %d is not in a paragraph", index)
+		}
+		table := &docs.Table{TableStyle: &docs.TableStyle{}}
+		for j := int64(0); j < r.InsertTable.Columns; j++ {
+			table.TableStyle.TableColumnProperties = append(table.TableStyle.TableColumnProperties, fakeNewColumnProperties())
+		}
+		for i := int64(0); i < r.InsertTable.Rows; i++ {
+			row := &docs.TableRow{}
+			for j := int64(0); j < r.InsertTable.Columns; j++ {
+				row.TableCells = append(row.TableCells, fakeNewCell())
+			}
+			table.TableRows = append(table.TableRows, row)
+		}
+		c := append([]*docs.StructuralElement{}, (*content)[:pos]...)
+		c = append(c, &docs.StructuralElement{Table: table})
+		*content = append(c, (*content)[pos:]...)
+		return nil
+	case r.InsertTableRow != nil:
+		table, err := fakeTableCellLocation(d, r.InsertTableRow.TableCellLocation)
+		if err != nil {
+			return err
+		}
+		loc := r.InsertTableRow.TableCellLocation
+		pos := loc.RowIndex
+		if r.InsertTableRow.InsertBelow {
+			pos++
+		}
+		row := &docs.TableRow{}
+		for range table.TableRows[loc.RowIndex].TableCells {
+			row.TableCells = append(row.TableCells, fakeNewCell())
+		}
+		rows := append([]*docs.TableRow{}, table.TableRows[:pos]...)
+		rows = append(rows, row)
+		table.TableRows = append(rows, table.TableRows[pos:]...)
+		return nil
+	case r.InsertTableColumn != nil:
+		table, err := fakeTableCellLocation(d, r.InsertTableColumn.TableCellLocation)
+		if err != nil {
+			return err
+		}
+		pos := r.InsertTableColumn.TableCellLocation.ColumnIndex
+		if r.InsertTableColumn.InsertRight {
+			pos++
+		}
+		for _, row := range table.TableRows {
+			p := pos
+			if p > int64(len(row.TableCells)) {
+				p = int64(len(row.TableCells))
+			}
+			cells := append([]*docs.TableCell{}, row.TableCells[:p]...)
+			cells = append(cells, fakeNewCell())
+			row.TableCells = append(cells, row.TableCells[p:]...)
+		}
+		if ts := table.TableStyle; ts != nil && pos <= int64(len(ts.TableColumnProperties)) {
+			props := append([]*docs.TableColumnProperties{}, ts.TableColumnProperties[:pos]...)
+			props = append(props, fakeNewColumnProperties())
+			ts.TableColumnProperties = append(props, ts.TableColumnProperties[pos:]...)
+		}
+		return nil
+	case r.DeleteTableRow != nil:
+		table, err := fakeTableCellLocation(d, r.DeleteTableRow.TableCellLocation)
+		if err != nil {
+			return err
+		}
+		if len(table.TableRows) == 1 {
+			return fmt.Errorf("the last row of the table cannot be deleted")
+		}
+		pos := r.DeleteTableRow.TableCellLocation.RowIndex
+		table.TableRows = append(table.TableRows[:pos], table.TableRows[pos+1:]...)
+		return nil
+	case r.DeleteTableColumn != nil:
+		table, err := fakeTableCellLocation(d, r.DeleteTableColumn.TableCellLocation)
+		if err != nil {
+			return err
+		}
+		if table.Columns == 1 {
+			return fmt.Errorf("the last column of the table cannot be deleted")
+		}
+		pos := r.DeleteTableColumn.TableCellLocation.ColumnIndex
+		for _, row := range table.TableRows {
+			if pos < int64(len(row.TableCells)) {
+				row.TableCells = append(row.TableCells[:pos], row.TableCells[pos+1:]...)
+			}
+		}
+		if ts := table.TableStyle; ts != nil && pos < int64(len(ts.TableColumnProperties)) {
+			ts.TableColumnProperties = append(ts.TableColumnProperties[:pos], ts.TableColumnProperties[pos+1:]...)
+		}
+		return nil
+	case r.UpdateTextStyle != nil:
+		rng := r.UpdateTextStyle.Range
+		if rng == nil || rng.StartIndex >= rng.EndIndex {
+			return fmt.Errorf("range is empty")
+		}
+		if r.UpdateTextStyle.TextStyle == nil || r.UpdateTextStyle.Fields == "" {
+			return fmt.Errorf("textStyle and fields are required")
+		}
+		return fakeUpdateTextStyle(d.Body.Content, rng.StartIndex, rng.EndIndex, r.UpdateTextStyle.TextStyle, r.UpdateTextStyle.Fields)
+	case r.MergeTableCells != nil, r.UnmergeTableCells != nil:
+		tableRange := &docs.TableRange{}
+		if r.MergeTableCells != nil {
+			tableRange = r.MergeTableCells.TableRange
+		} else {
+			tableRange = r.UnmergeTableCells.TableRange
+		}
+		if tableRange == nil {
+			return fmt.Errorf("tableRange is required")
+		}
+		table, err := fakeTableCellLocation(d, tableRange.TableCellLocation)
+		if err != nil {
+			return err
+		}
+		row, col := tableRange.TableCellLocation.RowIndex, tableRange.TableCellLocation.ColumnIndex
+		if tableRange.RowSpan < 1 || tableRange.ColumnSpan < 1 || row+tableRange.RowSpan > table.Rows || col+tableRange.ColumnSpan > table.Columns {
+			return fmt.Errorf("table range is outside of the table")
+		}
+		if r.UnmergeTableCells != nil {
+			for i := row; i < row+tableRange.RowSpan; i++ {
+				for j := col; j < col+tableRange.ColumnSpan; j++ {
+					if cs := table.TableRows[i].TableCells[j].TableCellStyle; cs != nil {
+						cs.RowSpan, cs.ColumnSpan = 1, 1
+					}
+				}
+			}
+			return nil
+		}
+		head := table.TableRows[row].TableCells[col]
+		for i := row; i < row+tableRange.RowSpan; i++ {
+			for j := col; j < col+tableRange.ColumnSpan; j++ {
+				cell := table.TableRows[i].TableCells[j]
+				if cell == head {
+					continue
+				}
+				if fakeCellText(cell) != "" {
+					if fakeCellText(head) == "" {
+						head.Content = cell.Content
+					} else {
+						head.Content = append(head.Content, cell.Content...)
+					}
+				}
+				cell.Content = []*docs.StructuralElement{fakeNewParagraph()}
+			}
+		}
+		if head.TableCellStyle == nil {
+			head.TableCellStyle = &docs.TableCellStyle{}
+		}
+		head.TableCellStyle.RowSpan = tableRange.RowSpan
+		head.TableCellStyle.ColumnSpan = tableRange.ColumnSpan
+		return nil
+	case r.PinTableHeaderRows != nil:
+		u := r.PinTableHeaderRows
+		if u.TableStartLocation == nil {
+			return fmt.Errorf("tableStartLocation is required")
+		}
+		table := fakeFindTable(d.Body.Content, u.TableStartLocation.Index)
+		if table == nil {
+			return fmt.Errorf("table is not found at index %d", u.TableStartLocation.Index)
+		}
+		if u.PinnedHeaderRowsCount < 0 || u.PinnedHeaderRowsCount > int64(len(table.TableRows)) {
+			return fmt.Errorf("pinnedHeaderRowsCount is outside of the table")
+		}
+		for i, row := range table.TableRows {
+			if row.TableRowStyle == nil {
+				row.TableRowStyle = &docs.TableRowStyle{}
+			}
+			row.TableRowStyle.TableHeader = int64(i) < u.PinnedHeaderRowsCount
+		}
+		return nil
+	case r.UpdateTableColumnProperties != nil:
+		u := r.UpdateTableColumnProperties
+		if u.TableStartLocation == nil || u.TableColumnProperties == nil || u.Fields == "" {
+			return fmt.Errorf("tableStartLocation, tableColumnProperties and fields are required")
+		}
+		table := fakeFindTable(d.Body.Content, u.TableStartLocation.Index)
+		if table == nil {
+			return fmt.Errorf("table is not found at index %d", u.TableStartLocation.Index)
+		}
+		if table.TableStyle == nil {
+			table.TableStyle = &docs.TableStyle{}
+		}
+		for int64(len(table.TableStyle.TableColumnProperties)) < table.Columns {
+			table.TableStyle.TableColumnProperties = append(table.TableStyle.TableColumnProperties, fakeNewColumnProperties())
+		}
+		columns := u.ColumnIndices
+		if len(columns) == 0 {
+			for j := int64(0); j < table.Columns; j++ {
+				columns = append(columns, j)
+			}
+		}
+		for _, j := range columns {
+			if j < 0 || j >= table.Columns {
+				return fmt.Errorf("column %d is outside of the table", j)
+			}
+			props := table.TableStyle.TableColumnProperties[j]
+			for _, field := range strings.Split(u.Fields, ",") {
+				switch strings.TrimSpace(field) {
+				case "*":
+					*props = *u.TableColumnProperties
+				case "width":
+					props.Width = u.TableColumnProperties.Width
+				case "widthType":
+					props.WidthType = u.TableColumnProperties.WidthType
+				default:
+					return fmt.Errorf("the field %q of tableColumnProperties is not supported by FakeBackend", field)
+				}
+			}
+			if props.WidthType == "FIXED_WIDTH" && (props.Width == nil || props.Width.Magnitude < 5) {
+				return fmt.Errorf("the width of column must be 5 PT or more")
+			}
+		}
+		return nil
+	case r.UpdateTableCellStyle != nil:
+		u := r.UpdateTableCellStyle
+		if u.TableRange == nil || u.TableCellStyle == nil || u.Fields == "" {
+			return fmt.Errorf("tableRange, tableCellStyle and fields are required")
+		}
+		table, err := fakeTableCellLocation(d, u.TableRange.TableCellLocation)
+		if err != nil {
+			return err
+		}
+		row, col := u.TableRange.TableCellLocation.RowIndex, u.TableRange.TableCellLocation.ColumnIndex
+		if u.TableRange.RowSpan < 1 || u.TableRange.ColumnSpan < 1 || row+u.TableRange.RowSpan > table.Rows || col+u.TableRange.ColumnSpan > table.Columns {
+			return fmt.Errorf("table range is outside of the table")
+		}
+		for i := row; i < row+u.TableRange.RowSpan; i++ {
+			for j := col; j < col+u.TableRange.ColumnSpan; j++ {
+				cell := table.TableRows[i].TableCells[j]
+				if err := fakeUpdateTableCellStyle(cell, u.TableCellStyle, u.Fields); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("the request is not supported by FakeBackend")
+}
+
+// fakeNotFound : Error for the Document and the file which are not found.
+func fakeNotFound() error {
+	return fakeError(http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+}
+
+// fakeError : Error like the error response of Docs API with the status.
+func fakeError(code int, status, message string) error {
+	body, _ := json.Marshal(map[string]interface{}{
+		"error": map[string]interface{}{"code": code, "message": message, "status": status},
+	})
+	return &googleapi.Error{
+		Code:    code,
+		Message: message,
+		Body:    string(body),
+	}
+}
+
+// fakeCopyDocument : Create a deep copy of Document.
+func fakeCopyDocument(doc *docs.Document) *docs.Document {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		panic(err)
+	}
+	d := &docs.Document{}
+	if err := json.Unmarshal(b, d); err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// fakeNewParagraph : Create an empty paragraph.
+func fakeNewParagraph() *docs.StructuralElement {
+	return &docs.StructuralElement{
+		Paragraph: &docs.Paragraph{
+			Elements: []*docs.ParagraphElement{{TextRun: &docs.TextRun{Content: "\n"}}},
+		},
+	}
+}
+
+// fakeNewCell : Create an empty table cell.
+func fakeNewCell() *docs.TableCell {
+	return &docs.TableCell{
+		Content: []*docs.StructuralElement{fakeNewParagraph()},
+		TableCellStyle: &docs.TableCellStyle{
+			RowSpan:    1,
+			ColumnSpan: 1,
+		},
+	}
+}
+
+// fakeNewColumnProperties : Create the properties of the column of the evenly distributed width.
+func fakeNewColumnProperties() *docs.TableColumnProperties {
+	return &docs.TableColumnProperties{WidthType: "EVENLY_DISTRIBUTED"}
+}
+
+// fakeLocation : Return the index of Location or EndOfSegmentLocation.
+func fakeLocation(d *docs.Document, l *docs.Location, el *docs.EndOfSegmentLocation) (int64, error) {
+	if l != nil {
+		if l.SegmentId != "" {
+			return 0, fmt.Errorf("segments except for the body are not supported")
+		}
+		return l.Index, nil
+	}
+	if el != nil {
+		if el.SegmentId != "" {
+			return 0, fmt.Errorf("segments except for the body are not supported")
+		}
+		content := d.Body.Content
+		return content[len(content)-1].EndIndex - 1, nil
+	}
+	return 0, fmt.Errorf("location is not set")
+}
+
+// fakeTableCellLocation : Return the table of TableCellLocation.
+func fakeTableCellLocation(d *docs.Document, l *docs.TableCellLocation) (*docs.Table, error) {
+	if l == nil || l.TableStartLocation == nil {
+		return nil, fmt.Errorf("table cell location is not set")
+	}
+	table := fakeFindTable(d.Body.Content, l.TableStartLocation.Index)
+	if table == nil {
+		return nil, fmt.Errorf("table is not found at index %d", l.TableStartLocation.Index)
+	}
+	if l.RowIndex < 0 || l.RowIndex >= int64(len(table.TableRows)) || l.ColumnIndex < 0 || l.ColumnIndex >= table.Columns {
+		return nil, fmt.Errorf("cell (%d, %d) is outside of the table", l.RowIndex, l.ColumnIndex)
+	}
+	return table, nil
+}
+
+// fakeFindTable : Find the table starting at index.
+func fakeFindTable(content []*docs.StructuralElement, index int64) *docs.Table {
+	for _, e := range content {
+		if e.Table == nil || index < e.StartIndex || index >= e.EndIndex {
+			continue
+		}
+		if e.StartIndex == index {
+			return e.Table
+		}
+		for _, row := range e.Table.TableRows {
+			for _, cell := range row.TableCells {
+				if t := fakeFindTable(cell.Content, index); t != nil {
+					return t
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fakeFindParagraph : Find the paragraph including index.
+func fakeFindParagraph(content []*docs.StructuralElement, index int64) *docs.StructuralElement {
+	for _, e := range content {
+		if index < e.StartIndex || index >= e.EndIndex {
+			continue
+		}
+		if e.Paragraph != nil {
+			return e
+		}
+		if e.Table != nil {
+			for _, row := range e.Table.TableRows {
+				for _, cell := range row.TableCells {
+					if index > cell.StartIndex && index < cell.EndIndex {
+						return fakeFindParagraph(cell.Content, index)
+					}
+				}
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// fakeFindContainer : Find the content including the paragraph starting at index, and the position of the paragraph.
+func fakeFindContainer(content *[]*docs.StructuralElement, index int64) (*[]*docs.StructuralElement, int) {
+	for i, e := range *content {
+		if e.Paragraph != nil && e.StartIndex == index {
+			return content, i
+		}
+		if e.Table != nil && index > e.StartIndex && index < e.EndIndex {
+			for _, row := range e.Table.TableRows {
+				for _, cell := range row.TableCells {
+					if index > cell.StartIndex && index < cell.EndIndex {
+						return fakeFindContainer(&cell.Content, index)
+					}
+				}
+			}
+		}
+	}
+	return nil, -1
+}
+
+// fakeInsertElement : Insert the paragraph element at index. The text run at index is split.
+func fakeInsertElement(d *docs.Document, index int64, pe *docs.ParagraphElement) error {
+	p := fakeFindParagraph(d.Body.Content, index)
+	if p == nil {
+		return fmt.Errorf("index %d is not in a paragraph", index)
+	}
+	var elements []*docs.ParagraphElement
+	inserted := false
+	for _, e := range p.Paragraph.Elements {
+		if !inserted && index >= e.StartIndex && index < e.EndIndex {
+			if e.TextRun != nil {
+				if pe.TextRun != nil && pe.TextRun.TextStyle == nil && e.TextRun.TextStyle != nil {
+					style := *e.TextRun.TextStyle
+					pe.TextRun.TextStyle = &style
+				}
+				if index > e.StartIndex {
+					left, right := utf16Split(e.TextRun.Content, index-e.StartIndex)
+					elements = append(elements, &docs.ParagraphElement{TextRun: &docs.TextRun{Content: left, TextStyle: e.TextRun.TextStyle}})
+					e = &docs.ParagraphElement{TextRun: &docs.TextRun{Content: right, TextStyle: e.TextRun.TextStyle}}
+				}
+			} else if index > e.StartIndex {
+				return fmt.Errorf("index %d is inside of the element", index)
+			}
+			elements = append(elements, pe)
+			inserted = true
+		}
+		elements = append(elements, e)
+	}
+	p.Paragraph.Elements = elements
+	return nil
+}
+
+// fakeDeleteRange : Delete the range from the content.
+func fakeDeleteRange(content []*docs.StructuralElement, start, end int64) ([]*docs.StructuralElement, error) {
+	var res []*docs.StructuralElement
+	last := content[len(content)-1]
+	for _, e := range content {
+		if e.EndIndex <= start || e.StartIndex >= end {
+			res = append(res, e)
+			continue
+		}
+		switch {
+		case e.Table != nil:
+			if start <= e.StartIndex && e.EndIndex <= end {
+				continue
+			}
+			var cell *docs.TableCell
+			for _, row := range e.Table.TableRows {
+				for _, c := range row.TableCells {
+					if start > c.StartIndex && end <= c.EndIndex {
+						cell = c
+					}
+				}
+			}
+			if cell == nil {
+				return nil, fmt.Errorf("range (%d, %d) includes a part of the table", start, end)
+			}
+			c, err := fakeDeleteRange(cell.Content, start, end)
+			if err != nil {
+				return nil, err
+			}
+			cell.Content = c
+			res = append(res, e)
+		case e.Paragraph != nil:
+			if e == last && end >= e.EndIndex {
+				return nil, fmt.Errorf("the last newline of the segment cannot be deleted")
+			}
+			var elements []*docs.ParagraphElement
+			for _, pe := range e.Paragraph.Elements {
+				if pe.EndIndex <= start || pe.StartIndex >= end {
+					elements = append(elements, pe)
+					continue
+				}
+				if pe.TextRun == nil {
+					continue
+				}
+				from, to := start, end
+				if from < pe.StartIndex {
+					from = pe.StartIndex
+				}
+				if to > pe.EndIndex {
+					to = pe.EndIndex
+				}
+				left, _ := utf16Split(pe.TextRun.Content, from-pe.StartIndex)
+				_, right := utf16Split(pe.TextRun.Content, to-pe.StartIndex)
+				elements = append(elements, &docs.ParagraphElement{TextRun: &docs.TextRun{Content: left + right, TextStyle: pe.TextRun.TextStyle}})
+			}
+			e.Paragraph.Elements = elements
+			res = append(res, e)
+		default:
+			return nil, fmt.Errorf("range (%d, %d) includes the element which cannot be deleted", start, end)
+		}
+	}
+	return res, nil
+}
+
+// fakeUpdateTextStyle : Update the style of the text runs in the range. The text runs are split at the boundaries of the range.
+func fakeUpdateTextStyle(content []*docs.StructuralElement, start, end int64, style *docs.TextStyle, fields string) error {
+	for _, e := range content {
+		if e.EndIndex <= start || e.StartIndex >= end {
+			continue
+		}
+		if e.Table != nil {
+			for _, row := range e.Table.TableRows {
+				for _, cell := range row.TableCells {
+					if err := fakeUpdateTextStyle(cell.Content, start, end, style, fields); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+		if e.Paragraph == nil {
+			continue
+		}
+		var elements []*docs.ParagraphElement
+		for _, pe := range e.Paragraph.Elements {
+			if pe.TextRun == nil || pe.EndIndex <= start || pe.StartIndex >= end {
+				elements = append(elements, pe)
+				continue
+			}
+			from, to := start, end
+			if from < pe.StartIndex {
+				from = pe.StartIndex
+			}
+			if to > pe.EndIndex {
+				to = pe.EndIndex
+			}
+			left, rest := utf16Split(pe.TextRun.Content, from-pe.StartIndex)
+			middle, right := utf16Split(rest, to-from)
+			if left != "" {
+				elements = append(elements, &docs.ParagraphElement{TextRun: &docs.TextRun{Content: left, TextStyle: pe.TextRun.TextStyle}})
+			}
+			updated, err := fakeMergeTextStyle(pe.TextRun.TextStyle, style, fields)
+			if err != nil {
+				return err
+			}
+			elements = append(elements, &docs.ParagraphElement{TextRun: &docs.TextRun{Content: middle, TextStyle: updated}})
+			if right != "" {
+				elements = append(elements, &docs.ParagraphElement{TextRun: &docs.TextRun{Content: right, TextStyle: pe.TextRun.TextStyle}})
+			}
+		}
+		e.Paragraph.Elements = elements
+	}
+	return nil
+}
+
+// fakeMergeTextStyle : Return the copy of base updated by the fields of style.
+func fakeMergeTextStyle(base, style *docs.TextStyle, fields string) (*docs.TextStyle, error) {
+	res := &docs.TextStyle{}
+	if base != nil {
+		*res = *base
+		res.ForceSendFields = nil
+	}
+	for _, field := range strings.Split(fields, ",") {
+		switch strings.TrimSpace(field) {
+		case "*":
+			*res = *style
+			res.ForceSendFields = nil
+		case "bold":
+			res.Bold = style.Bold
+		case "italic":
+			res.Italic = style.Italic
+		case "underline":
+			res.Underline = style.Underline
+		case "strikethrough":
+			res.Strikethrough = style.Strikethrough
+		case "weightedFontFamily":
+			res.WeightedFontFamily = style.WeightedFontFamily
+		case "fontSize":
+			res.FontSize = style.FontSize
+		case "foregroundColor":
+			res.ForegroundColor = style.ForegroundColor
+		case "backgroundColor":
+			res.BackgroundColor = style.BackgroundColor
+		case "link":
+			res.Link = style.Link
+		default:
+			return nil, fmt.Errorf("the field %q of textStyle is not supported by FakeBackend", field)
+		}
+	}
+	return res, nil
+}
+
+// fakeCellText : Return the text of the cell without the newlines.
+func fakeCellText(cell *docs.TableCell) string {
+	var text string
+	for _, e := range cell.Content {
+		if e.Paragraph == nil {
+			return "[NOT TEXT]"
+		}
+		for _, pe := range e.Paragraph.Elements {
+			if pe.TextRun == nil {
+				return "[NOT TEXT]"
+			}
+			text += strings.Replace(pe.TextRun.Content, "\n", "", -1)
+		}
+	}
+	return text
+}
+
+// fakeUpdateTableCellStyle : Update the style of the cell by the fields of style.
+func fakeUpdateTableCellStyle(cell *docs.TableCell, style *docs.TableCellStyle, fields string) error {
+	if cell.TableCellStyle == nil {
+		cell.TableCellStyle = &docs.TableCellStyle{RowSpan: 1, ColumnSpan: 1}
+	}
+	cs := cell.TableCellStyle
+	for _, field := range strings.Split(fields, ",") {
+		switch strings.TrimSpace(field) {
+		case "backgroundColor":
+			cs.BackgroundColor = style.BackgroundColor
+		case "borderTop":
+			cs.BorderTop = style.BorderTop
+		case "borderBottom":
+			cs.BorderBottom = style.BorderBottom
+		case "borderLeft":
+			cs.BorderLeft = style.BorderLeft
+		case "borderRight":
+			cs.BorderRight = style.BorderRight
+		case "paddingTop":
+			cs.PaddingTop = style.PaddingTop
+		case "paddingBottom":
+			cs.PaddingBottom = style.PaddingBottom
+		case "paddingLeft":
+			cs.PaddingLeft = style.PaddingLeft
+		case "paddingRight":
+			cs.PaddingRight = style.PaddingRight
+		case "contentAlignment":
+			cs.ContentAlignment = style.ContentAlignment
+		default:
+			return fmt.Errorf("the field %q of tableCellStyle is not supported by FakeBackend", field)
+		}
+	}
+	return nil
+}
+
+// fakeReindexDocument : Normalize the paragraphs and recalculate the indexes of Document.
+func fakeReindexDocument(d *docs.Document) {
+	d.Body.Content = fakeNormalize(d.Body.Content)
+	fakeReindex(d.Body.Content, 0)
+}
+
+// fakeNormalize : Split the paragraphs at the newlines, and merge the paragraph without the last newline into the next paragraph.
+// The adjacent text runs with the same style are merged like Docs API.
+func fakeNormalize(content []*docs.StructuralElement) []*docs.StructuralElement {
+	var res []*docs.StructuralElement
+	var cur *docs.StructuralElement
+	newParagraph := func(src *docs.Paragraph) *docs.StructuralElement {
+		p := *src
+		p.Elements = nil
+		return &docs.StructuralElement{Paragraph: &p}
+	}
+	for _, e := range content {
+		if e.Paragraph == nil {
+			if cur != nil {
+				fakeAppendText(cur, "\n", nil)
+				res = append(res, cur)
+				cur = nil
+			}
+			if e.Table != nil {
+				for _, row := range e.Table.TableRows {
+					for _, cell := range row.TableCells {
+						cell.Content = fakeNormalize(cell.Content)
+					}
+				}
+			}
+			res = append(res, e)
+			continue
+		}
+		if cur == nil {
+			cur = newParagraph(e.Paragraph)
+		}
+		for _, pe := range e.Paragraph.Elements {
+			if pe.TextRun == nil {
+				cur.Paragraph.Elements = append(cur.Paragraph.Elements, pe)
+				continue
+			}
+			text := pe.TextRun.Content
+			for text != "" {
+				i := strings.Index(text, "\n")
+				if i < 0 {
+					fakeAppendText(cur, text, pe.TextRun.TextStyle)
+					break
+				}
+				fakeAppendText(cur, text[:i+1], pe.TextRun.TextStyle)
+				res = append(res, cur)
+				cur = newParagraph(e.Paragraph)
+				text = text[i+1:]
+			}
+		}
+		if len(cur.Paragraph.Elements) == 0 {
+			cur = nil
+		}
+	}
+	if cur != nil {
+		fakeAppendText(cur, "\n", nil)
+		res = append(res, cur)
+	}
+	return res
+}
+
+// fakeAppendText : Append the text to the paragraph. When the last text run has the same style, the text is merged.
+func fakeAppendText(p *docs.StructuralElement, text string, style *docs.TextStyle) {
+	elements := p.Paragraph.Elements
+	if n := len(elements); n > 0 && elements[n-1].TextRun != nil {
+		last := elements[n-1].TextRun
+		if reflect.DeepEqual(last.TextStyle, style) {
+			elements[n-1] = &docs.ParagraphElement{TextRun: &docs.TextRun{Content: last.Content + text, TextStyle: last.TextStyle}}
+			return
+		}
+	}
+	p.Paragraph.Elements = append(elements, &docs.ParagraphElement{TextRun: &docs.TextRun{Content: text, TextStyle: style}})
+}
+
+// fakeReindex : Recalculate the indexes of the content from index. The end index is returned.
+func fakeReindex(content []*docs.StructuralElement, index int64) int64 {
+	for _, e := range content {
+		length := e.EndIndex - e.StartIndex
+		e.StartIndex = index
+		switch {
+		case e.Paragraph != nil:
+			for _, pe := range e.Paragraph.Elements {
+				l := pe.EndIndex - pe.StartIndex
+				if pe.TextRun != nil {
+					l = utf16Len(pe.TextRun.Content)
+				} else if pe.InlineObjectElement != nil {
+					l = 1
+				}
+				pe.StartIndex = index
+				index += l
+				pe.EndIndex = index
+			}
+		case e.Table != nil:
+			index++
+			for _, row := range e.Table.TableRows {
+				row.StartIndex = index
+				index++
+				for _, cell := range row.TableCells {
+					cell.StartIndex = index
+					index = fakeReindex(cell.Content, index+1)
+					cell.EndIndex = index
+				}
+				row.EndIndex = index
+			}
+			index++
+			e.Table.Rows = int64(len(e.Table.TableRows))
+			if e.Table.Rows > 0 {
+				e.Table.Columns = int64(len(e.Table.TableRows[0].TableCells))
+			}
+		case e.SectionBreak != nil:
+			index++
+		default:
+			index += length
+		}
+		e.EndIndex = index
+	}
+	return index
+}
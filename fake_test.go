@@ -0,0 +1,339 @@
+package gdoctableapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// fakeLayout : Describe the indexes of content like `P1-3{1-3"a\n"}`.
+// S is a section break, P is a paragraph, T is a table, R is a row and C is a cell. An inline object is shown as "*".
+func fakeLayout(content []*docs.StructuralElement) string {
+	var res []string
+	for _, e := range content {
+		s := fmt.Sprintf("%d-%d", e.StartIndex, e.EndIndex)
+		switch {
+		case e.SectionBreak != nil:
+			res = append(res, "S"+s)
+		case e.Paragraph != nil:
+			var elements []string
+			for _, pe := range e.Paragraph.Elements {
+				text := "*"
+				if pe.TextRun != nil {
+					text = pe.TextRun.Content
+				}
+				elements = append(elements, fmt.Sprintf("%d-%d%q", pe.StartIndex, pe.EndIndex, text))
+			}
+			res = append(res, "P"+s+"{"+strings.Join(elements, " ")+"}")
+		case e.Table != nil:
+			var rows []string
+			for _, row := range e.Table.TableRows {
+				var cells []string
+				for _, cell := range row.TableCells {
+					cells = append(cells, fmt.Sprintf("C%d-%d[%s]", cell.StartIndex, cell.EndIndex, fakeLayout(cell.Content)))
+				}
+				rows = append(rows, fmt.Sprintf("R%d-%d[%s]", row.StartIndex, row.EndIndex, strings.Join(cells, " ")))
+			}
+			res = append(res, "T"+s+"["+strings.Join(rows, " ")+"]")
+		}
+	}
+	return strings.Join(res, " ")
+}
+
+// fakeApply : Apply the requests to "doc" by one batchUpdate.
+func fakeApply(f *FakeBackend, requests ...*docs.Request) error {
+	_, err := f.BatchUpdate(context.Background(), "doc", &docs.BatchUpdateDocumentRequest{Requests: requests})
+	return err
+}
+
+func insertText(index int64, text string) *docs.Request {
+	return &docs.Request{InsertText: &docs.InsertTextRequest{Location: &docs.Location{Index: index}, Text: text}}
+}
+
+func insertTable(index, rows, cols int64) *docs.Request {
+	return &docs.Request{InsertTable: &docs.InsertTableRequest{Location: &docs.Location{Index: index}, Rows: rows, Columns: cols}}
+}
+
+func deleteRange(start, end int64) *docs.Request {
+	return createDeleteContentRangeRequest(start, end)
+}
+
+func cellLocation(row, col int64) *docs.TableCellLocation {
+	return &docs.TableCellLocation{TableStartLocation: &docs.Location{Index: 2}, RowIndex: row, ColumnIndex: col}
+}
+
+// emptyTable : Layout of the 2 x 2 empty table inserted to index 1 of the empty Document.
+// The table starts at 2, because a newline is inserted before the table. The 1st cell is at the table start + 2,
+// and its paragraph is at the table start + 3. Each empty cell is 2 indexes long, and the table ends with 1 index after the last row.
+const emptyTable = `S0-1 P1-2{1-2"\n"} T2-14[` +
+	`R3-8[C4-6[P5-6{5-6"\n"}] C6-8[P7-8{7-8"\n"}]] ` +
+	`R8-13[C9-11[P10-11{10-11"\n"}] C11-13[P12-13{12-13"\n"}]]] ` +
+	`P14-15{14-15"\n"}`
+
+func TestFakeBackendRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests [][]*docs.Request // Each element is applied by one batchUpdate.
+		want     string
+	}{
+		{
+			name:     "empty Document",
+			requests: nil,
+			want:     `S0-1 P1-2{1-2"\n"}`,
+		},
+		{
+			name:     "InsertText with newlines and a surrogate pair",
+			requests: [][]*docs.Request{{insertText(1, "ab\n🍣")}},
+			want:     `S0-1 P1-4{1-4"ab\n"} P4-7{4-7"🍣\n"}`,
+		},
+		{
+			name:     "InsertText into the text",
+			requests: [][]*docs.Request{{insertText(1, "ad"), insertText(2, "日本")}},
+			want:     `S0-1 P1-6{1-6"a日本d\n"}`,
+		},
+		{
+			name:     "DeleteContentRange across paragraphs",
+			requests: [][]*docs.Request{{insertText(1, "ab\n🍣")}, {deleteRange(2, 4)}},
+			want:     `S0-1 P1-5{1-5"a🍣\n"}`,
+		},
+		{
+			name: "InsertInlineImage",
+			requests: [][]*docs.Request{{insertText(1, "ab")}, {{InsertInlineImage: &docs.InsertInlineImageRequest{
+				Location: &docs.Location{Index: 2},
+				Uri:      "https://example.com/a.png",
+			}}}},
+			want: `S0-1 P1-5{1-2"a" 2-3"*" 3-5"b\n"}`,
+		},
+		{
+			name:     "InsertTable",
+			requests: [][]*docs.Request{{insertTable(1, 2, 2)}},
+			want:     emptyTable,
+		},
+		{
+			name:     "InsertText into cells",
+			requests: [][]*docs.Request{{insertTable(1, 2, 2)}, {insertText(12, "b"), insertText(7, "xy")}},
+			want: `S0-1 P1-2{1-2"\n"} T2-17[` +
+				`R3-10[C4-6[P5-6{5-6"\n"}] C6-10[P7-10{7-10"xy\n"}]] ` +
+				`R10-16[C11-13[P12-13{12-13"\n"}] C13-16[P14-16{14-16"b\n"}]]] ` +
+				`P17-18{17-18"\n"}`,
+		},
+		{
+			name:     "DeleteContentRange in a cell",
+			requests: [][]*docs.Request{{insertTable(1, 2, 2)}, {insertText(7, "xyz")}, {deleteRange(7, 9)}},
+			want: `S0-1 P1-2{1-2"\n"} T2-15[` +
+				`R3-9[C4-6[P5-6{5-6"\n"}] C6-9[P7-9{7-9"z\n"}]] ` +
+				`R9-14[C10-12[P11-12{11-12"\n"}] C12-14[P13-14{13-14"\n"}]]] ` +
+				`P15-16{15-16"\n"}`,
+		},
+		{
+			name: "InsertTableRow below",
+			requests: [][]*docs.Request{{insertTable(1, 2, 2)}, {insertText(5, "a")}, {{InsertTableRow: &docs.InsertTableRowRequest{
+				TableCellLocation: cellLocation(0, 0),
+				InsertBelow:       true,
+			}}}},
+			want: `S0-1 P1-2{1-2"\n"} T2-20[` +
+				`R3-9[C4-7[P5-7{5-7"a\n"}] C7-9[P8-9{8-9"\n"}]] ` +
+				`R9-14[C10-12[P11-12{11-12"\n"}] C12-14[P13-14{13-14"\n"}]] ` +
+				`R14-19[C15-17[P16-17{16-17"\n"}] C17-19[P18-19{18-19"\n"}]]] ` +
+				`P20-21{20-21"\n"}`,
+		},
+		{
+			name: "InsertTableRow above",
+			requests: [][]*docs.Request{{insertTable(1, 2, 2)}, {insertText(5, "a")}, {{InsertTableRow: &docs.InsertTableRowRequest{
+				TableCellLocation: cellLocation(0, 0),
+			}}}},
+			want: `S0-1 P1-2{1-2"\n"} T2-20[` +
+				`R3-8[C4-6[P5-6{5-6"\n"}] C6-8[P7-8{7-8"\n"}]] ` +
+				`R8-14[C9-12[P10-12{10-12"a\n"}] C12-14[P13-14{13-14"\n"}]] ` +
+				`R14-19[C15-17[P16-17{16-17"\n"}] C17-19[P18-19{18-19"\n"}]]] ` +
+				`P20-21{20-21"\n"}`,
+		},
+		{
+			name: "InsertTableColumn right",
+			requests: [][]*docs.Request{{insertTable(1, 2, 2)}, {insertText(5, "a")}, {{InsertTableColumn: &docs.InsertTableColumnRequest{
+				TableCellLocation: cellLocation(0, 0),
+				InsertRight:       true,
+			}}}},
+			want: `S0-1 P1-2{1-2"\n"} T2-19[` +
+				`R3-11[C4-7[P5-7{5-7"a\n"}] C7-9[P8-9{8-9"\n"}] C9-11[P10-11{10-11"\n"}]] ` +
+				`R11-18[C12-14[P13-14{13-14"\n"}] C14-16[P15-16{15-16"\n"}] C16-18[P17-18{17-18"\n"}]]] ` +
+				`P19-20{19-20"\n"}`,
+		},
+		{
+			name: "InsertTableColumn left",
+			requests: [][]*docs.Request{{insertTable(1, 2, 2)}, {insertText(5, "a")}, {{InsertTableColumn: &docs.InsertTableColumnRequest{
+				TableCellLocation: cellLocation(0, 0),
+			}}}},
+			want: `S0-1 P1-2{1-2"\n"} T2-19[` +
+				`R3-11[C4-6[P5-6{5-6"\n"}] C6-9[P7-9{7-9"a\n"}] C9-11[P10-11{10-11"\n"}]] ` +
+				`R11-18[C12-14[P13-14{13-14"\n"}] C14-16[P15-16{15-16"\n"}] C16-18[P17-18{17-18"\n"}]]] ` +
+				`P19-20{19-20"\n"}`,
+		},
+		{
+			name: "DeleteTableRow",
+			requests: [][]*docs.Request{{insertTable(1, 2, 2)}, {insertText(12, "b"), insertText(5, "a")}, {{DeleteTableRow: &docs.DeleteTableRowRequest{
+				TableCellLocation: cellLocation(0, 0),
+			}}}},
+			want: `S0-1 P1-2{1-2"\n"} T2-10[` +
+				`R3-9[C4-6[P5-6{5-6"\n"}] C6-9[P7-9{7-9"b\n"}]]] ` +
+				`P10-11{10-11"\n"}`,
+		},
+		{
+			name: "DeleteTableColumn",
+			requests: [][]*docs.Request{{insertTable(1, 2, 2)}, {insertText(12, "b"), insertText(5, "a")}, {{DeleteTableColumn: &docs.DeleteTableColumnRequest{
+				TableCellLocation: cellLocation(0, 0),
+			}}}},
+			want: `S0-1 P1-2{1-2"\n"} T2-11[` +
+				`R3-6[C4-6[P5-6{5-6"\n"}]] ` +
+				`R6-10[C7-10[P8-10{8-10"b\n"}]]] ` +
+				`P11-12{11-12"\n"}`,
+		},
+		{
+			name:     "DeleteContentRange of a table",
+			requests: [][]*docs.Request{{insertTable(1, 2, 2)}, {deleteRange(2, 14)}},
+			want:     `S0-1 P1-2{1-2"\n"} P2-3{2-3"\n"}`,
+		},
+		{
+			name: "UpdateTextStyle",
+			requests: [][]*docs.Request{{insertText(1, "abc")}, {
+				createUpdateTextStyleRequest(2, 3, &docs.TextStyle{Bold: true}, "bold"),
+			}},
+			want: `S0-1 P1-5{1-2"a" 2-3"b" 3-5"c\n"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFakeBackend()
+			f.NewDocument("doc")
+			for i, requests := range tt.requests {
+				if err := fakeApply(f, requests...); err != nil {
+					t.Fatalf("batchUpdate %d: %v", i, err)
+				}
+			}
+			if got := fakeLayout(f.Document("doc").Body.Content); got != tt.want {
+				t.Errorf("layout =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakeBackendStyleRequests(t *testing.T) {
+	f := NewFakeBackend()
+	f.NewDocument("doc")
+	if err := fakeApply(f, insertTable(1, 2, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fakeApply(f, insertText(12, "d"), insertText(5, "a")); err != nil {
+		t.Fatal(err)
+	}
+	requests := []*docs.Request{
+		{UpdateTableCellStyle: &docs.UpdateTableCellStyleRequest{
+			TableRange:     &docs.TableRange{TableCellLocation: cellLocation(0, 0), RowSpan: 1, ColumnSpan: 2},
+			TableCellStyle: &docs.TableCellStyle{ContentAlignment: "MIDDLE"},
+			Fields:         "contentAlignment",
+		}},
+		{UpdateTableColumnProperties: &docs.UpdateTableColumnPropertiesRequest{
+			TableStartLocation:    &docs.Location{Index: 2},
+			ColumnIndices:         []int64{1},
+			TableColumnProperties: &docs.TableColumnProperties{WidthType: "FIXED_WIDTH", Width: &docs.Dimension{Magnitude: 50, Unit: "PT"}},
+			Fields:                "width,widthType",
+		}},
+		createPinTableHeaderRowsRequest(2, 1),
+	}
+	if err := fakeApply(f, requests...); err != nil {
+		t.Fatal(err)
+	}
+	before := fakeLayout(f.Document("doc").Body.Content)
+	table := testTable(t, f)
+	for j, cell := range table.TableRows[0].TableCells {
+		if cell.TableCellStyle == nil || cell.TableCellStyle.ContentAlignment != "MIDDLE" {
+			t.Errorf("contentAlignment of cell (0, %d) was not updated", j)
+		}
+	}
+	if props := table.TableStyle.TableColumnProperties; props[0].WidthType != "EVENLY_DISTRIBUTED" || props[1].WidthType != "FIXED_WIDTH" || props[1].Width.Magnitude != 50 {
+		t.Errorf("column properties were not updated: %+v %+v", props[0], props[1])
+	}
+	if !table.TableRows[0].TableRowStyle.TableHeader || table.TableRows[1].TableRowStyle.TableHeader {
+		t.Error("header row was not pinned")
+	}
+
+	// The content of the merged cells is moved to the head cell, and the indexes are not changed.
+	merge := &docs.Request{MergeTableCells: &docs.MergeTableCellsRequest{
+		TableRange: &docs.TableRange{TableCellLocation: cellLocation(0, 0), RowSpan: 2, ColumnSpan: 2},
+	}}
+	if err := fakeApply(f, merge); err != nil {
+		t.Fatal(err)
+	}
+	table = testTable(t, f)
+	if s := table.TableRows[0].TableCells[0].TableCellStyle; s.RowSpan != 2 || s.ColumnSpan != 2 {
+		t.Errorf("span = %d, %d, want 2, 2", s.RowSpan, s.ColumnSpan)
+	}
+	if got, want := fakeLayout(table.TableRows[0].TableCells[0].Content), `P5-7{5-7"a\n"} P7-9{7-9"d\n"}`; got != want {
+		t.Errorf("layout of the head cell = %s, want %s", got, want)
+	}
+	unmerge := &docs.Request{UnmergeTableCells: &docs.UnmergeTableCellsRequest{
+		TableRange: &docs.TableRange{TableCellLocation: cellLocation(0, 0), RowSpan: 2, ColumnSpan: 2},
+	}}
+	if err := fakeApply(f, unmerge); err != nil {
+		t.Fatal(err)
+	}
+	if s := testTable(t, f).TableRows[0].TableCells[0].TableCellStyle; s.RowSpan != 1 || s.ColumnSpan != 1 {
+		t.Errorf("span = %d, %d after unmerging, want 1, 1", s.RowSpan, s.ColumnSpan)
+	}
+	if before == fakeLayout(f.Document("doc").Body.Content) {
+		t.Error("content was not moved by merging")
+	}
+}
+
+func TestFakeBackendInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *docs.Request
+	}{
+		{"last newline of the body", deleteRange(14, 15)},
+		{"part of the table", deleteRange(3, 6)},
+		{"index inside of the table structure", insertText(4, "a")},
+		{"empty text", insertText(1, "")},
+		{"outside of the table", &docs.Request{DeleteTableRow: &docs.DeleteTableRowRequest{TableCellLocation: cellLocation(2, 0)}}},
+		{"no table", &docs.Request{InsertTableRow: &docs.InsertTableRowRequest{TableCellLocation: &docs.TableCellLocation{TableStartLocation: &docs.Location{Index: 1}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFakeBackend()
+			f.NewDocument("doc")
+			if err := fakeApply(f, insertTable(1, 2, 2)); err != nil {
+				t.Fatal(err)
+			}
+			revision := f.Document("doc").RevisionId
+			if err := fakeApply(f, tt.request); err == nil {
+				t.Error("error was not returned")
+			}
+			if d := f.Document("doc"); d.RevisionId != revision || fakeLayout(d.Body.Content) != emptyTable {
+				t.Error("Document was changed by the invalid request")
+			}
+		})
+	}
+}
+
+func TestFakeBackendRequiredRevisionID(t *testing.T) {
+	f := NewFakeBackend()
+	f.NewDocument("doc")
+	req := &docs.BatchUpdateDocumentRequest{
+		Requests:     []*docs.Request{insertText(1, "a")},
+		WriteControl: &docs.WriteControl{RequiredRevisionId: "0"},
+	}
+	if _, err := f.BatchUpdate(context.Background(), "doc", req); err == nil {
+		t.Error("error was not returned for the old revision")
+	}
+	req.WriteControl.RequiredRevisionId = f.Document("doc").RevisionId
+	res, err := f.BatchUpdate(context.Background(), "doc", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.WriteControl.RequiredRevisionId != f.Document("doc").RevisionId {
+		t.Error("revision ID of the response is not the latest revision")
+	}
+}
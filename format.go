@@ -0,0 +1,153 @@
+// Package gdoctableapp (format.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the conversion of values to the strings of cells.
+package gdoctableapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatter : Function for converting a value to the string of the cell.
+type Formatter func(v interface{}) (string, error)
+
+// formatValue : Convert the value of the column to string.
+// The formatter of the column is used first, and the formatter of the table is used next.
+func (o *obj) formatValue(v interface{}, col int64) (string, error) {
+	if f, ok := o.params.FormatterP.Columns[col]; ok && f != nil {
+		return f(v)
+	}
+	if f := o.params.FormatterP.Table; f != nil {
+		return f(v)
+	}
+	return convertStr(v)
+}
+
+// convertStr : Convert value to string.
+// string, bool, integers, floats, time.Time (RFC3339), json.Number and fmt.Stringer can be used.
+func convertStr(v interface{}) (string, error) {
+	switch value := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float64:
+		return fmt.Sprint(value), nil
+	case float32:
+		return strconv.FormatFloat(float64(value), 'g', -1, 32), nil
+	case json.Number:
+		return value.String(), nil
+	case time.Time:
+		return value.Format(time.RFC3339), nil
+	case *time.Time:
+		if value == nil {
+			return "", nil
+		}
+		return value.Format(time.RFC3339), nil
+	case fmt.Stringer:
+		return value.String(), nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64), nil
+	}
+	return "", fmt.Errorf("error: Unknown value: %+v, %T", v, v)
+}
+
+// toFloat : Convert the numeric value to float64.
+func toFloat(v interface{}) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// NumberFormatter : Create Formatter for numbers.
+// decimals is the number of decimal places. When decimals is negative, the smallest number of digits is used.
+// thousandsSeparator is inserted every 3 digits of the integer part. e.g. NumberFormatter(2, ",") converts 1234.5 to "1,234.50".
+// The values except for numbers are converted by the default conversion.
+func NumberFormatter(decimals int, thousandsSeparator string) Formatter {
+	return func(v interface{}) (string, error) {
+		f, ok := toFloat(v)
+		if !ok || math.IsNaN(f) || math.IsInf(f, 0) {
+			return convertStr(v)
+		}
+		s := strconv.FormatFloat(f, 'f', decimals, 64)
+		if thousandsSeparator == "" {
+			return s, nil
+		}
+		sign := ""
+		if strings.HasPrefix(s, "-") {
+			sign = "-"
+			s = s[1:]
+		}
+		intPart, fracPart := s, ""
+		if i := strings.Index(s, "."); i >= 0 {
+			intPart, fracPart = s[:i], s[i:]
+		}
+		var b strings.Builder
+		for i, c := range intPart {
+			if i > 0 && (len(intPart)-i)%3 == 0 {
+				b.WriteString(thousandsSeparator)
+			}
+			b.WriteRune(c)
+		}
+		return sign + b.String() + fracPart, nil
+	}
+}
+
+// DateFormatter : Create Formatter for time.Time with layout. e.g. DateFormatter("2006-01-02")
+// The values except for time.Time are converted by the default conversion.
+func DateFormatter(layout string) Formatter {
+	return func(v interface{}) (string, error) {
+		switch value := v.(type) {
+		case time.Time:
+			return value.Format(layout), nil
+		case *time.Time:
+			if value != nil {
+				return value.Format(layout), nil
+			}
+		}
+		return convertStr(v)
+	}
+}
+
+// BoolFormatter : Create Formatter for bool. e.g. BoolFormatter("Yes", "No")
+// The values except for bool are converted by the default conversion.
+func BoolFormatter(trueText, falseText string) Formatter {
+	return func(v interface{}) (string, error) {
+		if b, ok := v.(bool); ok {
+			if b {
+				return trueText, nil
+			}
+			return falseText, nil
+		}
+		return convertStr(v)
+	}
+}
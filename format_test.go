@@ -0,0 +1,100 @@
+package gdoctableapp
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"testing"
+	"time"
+)
+
+type testName string
+
+type testLevel uint8
+
+func TestConvertStr(t *testing.T) {
+	date := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	var nilTime *time.Time
+	for _, c := range []struct {
+		v    interface{}
+		want string
+	}{
+		{nil, ""},
+		{"text", "text"},
+		{true, "true"},
+		{-12, "-12"},
+		{int8(-8), "-8"},
+		{uint64(18446744073709551615), "18446744073709551615"},
+		{1.5, "1.5"},
+		{float32(0.1), "0.1"},
+		{json.Number("1e3"), "1e3"},
+		{date, "2023-01-02T03:04:05Z"},
+		{&date, "2023-01-02T03:04:05Z"},
+		{nilTime, ""},
+		{net.IPv4(192, 168, 0, 1), "192.168.0.1"},
+		{testName("name"), "name"},
+		{testLevel(3), "3"},
+	} {
+		got, err := convertStr(c.v)
+		if err != nil {
+			t.Errorf("convertStr(%#v): %v", c.v, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("convertStr(%#v) = %q, want %q", c.v, got, c.want)
+		}
+	}
+	for _, v := range []interface{}{[]string{"a"}, map[string]int{}, struct{}{}} {
+		if _, err := convertStr(v); err == nil {
+			t.Errorf("no error for %#v", v)
+		}
+	}
+}
+
+func TestFormatters(t *testing.T) {
+	date := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, c := range []struct {
+		name string
+		f    Formatter
+		v    interface{}
+		want string
+	}{
+		{"number", NumberFormatter(2, ","), 1234.5, "1,234.50"},
+		{"negative number", NumberFormatter(0, ","), -1234567, "-1,234,567"},
+		{"small number", NumberFormatter(1, ","), 123.45, "123.5"},
+		{"smallest digits", NumberFormatter(-1, " "), float32(1234.25), "1 234.25"},
+		{"without separator", NumberFormatter(3, ""), uint16(12345), "12345.000"},
+		{"json.Number", NumberFormatter(1, ","), json.Number("9999.99"), "10,000.0"},
+		{"NaN", NumberFormatter(2, ","), math.NaN(), "NaN"},
+		{"not number", NumberFormatter(2, ","), "1234", "1234"},
+		{"date", DateFormatter("2006/01/02"), date, "2023/01/02"},
+		{"pointer of date", DateFormatter("15:04"), &date, "03:04"},
+		{"not date", DateFormatter("2006/01/02"), 1, "1"},
+		{"true", BoolFormatter("Yes", "No"), true, "Yes"},
+		{"false", BoolFormatter("Yes", "No"), false, "No"},
+		{"not bool", BoolFormatter("Yes", "No"), "true", "true"},
+	} {
+		got, err := c.f(c.v)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestSetColumnFormatter(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"", "", ""}})
+	values := [][]interface{}{{1234.6, true, 1234.6}}
+	p := New().Docs("doc").SetBackend(f).
+		SetFormatter(NumberFormatter(0, ",")).
+		SetColumnFormatter(1, BoolFormatter("o", "x")).
+		SetValuesBy2DArray(values)
+	if _, err := p.Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	// The column formatter is used instead of the table formatter, and the table formatter is used for the other columns.
+	assertValues(t, testValues(t, f), [][]string{{"1,235", "o", "1,235"}})
+}
@@ -3,6 +3,10 @@
 // This file includes handler method.
 package gdoctableapp
 
+import (
+	"fmt"
+)
+
 // checkOutputValues : Check output values.
 func (o *obj) checkOutputValues() {
 	if !o.params.ShowAPIResponseFlag {
@@ -12,11 +16,15 @@ func (o *obj) checkOutputValues() {
 
 // handler : Handler of gdoctableapp
 func (o *obj) handler() (*Result, error) {
+	o.result.LibraryVersion = version
+	if len(o.params.Operations) > 0 {
+		return o.operationsHandler()
+	}
 	if err := o.optionChecker(); err != nil {
 		return nil, err
 	}
+	o.withObjects = o.params.Works.DoGetCells
 
-	o.result.LibraryVersion = version
 	if !o.params.Works.DoCreateTable {
 		if o.params.Works.DoGetTables {
 			if err := o.getAllTables(); err != nil {
@@ -28,77 +36,196 @@ func (o *obj) handler() (*Result, error) {
 			}
 		}
 	}
+	if err := o.work(); err != nil {
+		return nil, err
+	}
+	o.checkOutputValues()
+	return &o.result, nil
+}
+
+// operationsHandler : Handler for the chained operations. The operations are run in order.
+// The table is retrieved again only when the indexes of the table were shifted by the previous operation.
+func (o *obj) operationsHandler() (*Result, error) {
+	if o.countWorks() > 0 {
+		return nil, fmt.Errorf("When Chain() is used, please use the methods in the operations of Chain()")
+	}
+	base := o.params
+	o.chain = true
+	for _, op := range base.Operations {
+		if op.Works.DoGetCells {
+			o.withObjects = true
+		}
+	}
+	for i, op := range base.Operations {
+		o.params = *op
+		o.params.Client = base.Client
+		o.params.DocumentID = base.DocumentID
+		o.params.TableIdx = base.TableIdx
+		o.params.TableSelectorP = base.TableSelectorP
+		o.params.ShowAPIResponseFlag = base.ShowAPIResponseFlag
+		o.params.DryRunFlag = base.DryRunFlag
+		o.params.Operations = nil
+		if o.params.FormatterP.Table == nil && o.params.FormatterP.Columns == nil {
+			o.params.FormatterP = base.FormatterP
+		}
+		if err := o.optionChecker(); err != nil {
+			return nil, fmt.Errorf("Operation %d: %v", i, err)
+		}
+		if o.params.Works.DoGetTables {
+			o.docTables = nil
+			if err := o.getAllTables(); err != nil {
+				return nil, err
+			}
+		} else if !o.params.Works.DoCreateTable && (o.docTable == nil || o.tableStale) {
+			if o.params.DryRunFlag && o.docTable != nil {
+				return nil, fmt.Errorf("Operation %d: DryRun cannot create the requests after the indexes of the table were shifted by the previous operation", i)
+			}
+			if err := o.getTable(); err != nil {
+				return nil, err
+			}
+			// The selected table is used for the next operations, even when the values of the table are changed.
+			base.TableIdx = o.params.TableIdx
+			base.TableSelectorP.By = ""
+		}
+		if err := o.work(); err != nil {
+			return nil, err
+		}
+	}
+	if err := o.flushRequests(); err != nil {
+		return nil, err
+	}
+	o.params = base
+	o.checkOutputValues()
+	return &o.result, nil
+}
 
+// work : Run the method selected by Works.
+func (o *obj) work() error {
 	// getTables
 	if o.params.Works.DoGetTables {
-		o.getTables().checkOutputValues()
-		return &o.result, nil
+		o.getTables()
+		return nil
 	}
 
 	// getValues
 	if o.params.Works.DoGetValues {
 		values, err := o.getValues()
 		if err != nil {
-			return nil, err
+			return err
 		}
 		o.result.Values = values
-		o.checkOutputValues()
-		return &o.result, nil
+		o.result.MergedCells = o.mergedCells
+		if o.params.ValuesInto != nil {
+			return UnmarshalValues(values, o.params.ValuesInto)
+		}
+		return nil
+	}
+
+	// getCells
+	if o.params.Works.DoGetCells {
+		o.result.Cells = o.getCells()
+		return nil
 	}
 
 	// setValues
 	if o.params.Works.DoValuesArray || o.params.Works.DoValuesObject {
-		if err := o.setValues(); err != nil {
-			return nil, err
-		}
-		o.checkOutputValues()
-		return &o.result, nil
+		return o.setValues()
 	}
 
 	// deleteTable
 	if o.params.Works.DoDeleteTable {
-		if err := o.deleteTable(); err != nil {
-			return nil, err
-		}
-		o.checkOutputValues()
-		return &o.result, nil
+		return o.deleteTable()
 	}
 
 	// deleteRowsColumns
 	if o.params.Works.DoDeleteRowsColumns {
-		if err := o.deleteRowsColumns(); err != nil {
-			return nil, err
-		}
-		o.checkOutputValues()
-		return &o.result, nil
+		return o.deleteRowsColumns()
+	}
+
+	// deleteRowsWhere
+	if o.params.Works.DoDeleteRowsWhere {
+		return o.deleteRowsWhere()
 	}
 
 	// createTable
 	if o.params.Works.DoCreateTable {
-		if err := o.crateTable(); err != nil {
-			return nil, err
-		}
-		o.checkOutputValues()
-		return &o.result, nil
+		return o.crateTable()
+	}
+
+	// clearRange
+	if o.params.Works.DoClearRange {
+		return o.clearRange()
+	}
+
+	// clearTable
+	if o.params.Works.DoClearTable {
+		return o.clearTable()
 	}
 
 	// appendRow
 	if o.params.Works.DoAppendRow {
-		if err := o.appendRow(); err != nil {
-			return nil, err
-		}
-		o.checkOutputValues()
-		return &o.result, nil
+		return o.appendRow()
+	}
+
+	// mergeCells
+	if o.params.Works.DoMergeCells || o.params.Works.DoUnmergeCells {
+		return o.mergeCells(o.params.Works.DoUnmergeCells)
+	}
+
+	// pinHeaderRows
+	if o.params.Works.DoPinHeaderRows {
+		return o.pinHeaderRows()
+	}
+
+	// setColumnWidths
+	if o.params.Works.DoColumnWidths {
+		return o.setColumnWidths()
+	}
+
+	// setCellStyle
+	if o.params.Works.DoCellStyle {
+		return o.setCellStyle()
+	}
+
+	// insertRows
+	if o.params.Works.DoInsertRows {
+		return o.insertRows()
+	}
+
+	// insertColumns
+	if o.params.Works.DoInsertColumns {
+		return o.insertColumns()
+	}
+
+	// moveRow
+	if o.params.Works.DoMoveRow {
+		return o.moveRow()
+	}
+
+	// moveColumn
+	if o.params.Works.DoMoveColumn {
+		return o.moveColumn()
+	}
+
+	// sortRows
+	if o.params.Works.DoSortRows {
+		return o.sortRows()
+	}
+
+	// upsertRows
+	if o.params.Works.DoUpsertRows {
+		return o.upsertRows()
+	}
+
+	// syncTable
+	if o.params.Works.DoSyncTable {
+		return o.syncTable()
 	}
 
 	// replaceTextsToImages
 	if o.params.Works.DoReplaceTextsToImagesByURL || o.params.Works.DoReplaceTextsToImagesByFile {
-		if err := o.replaceTextsToImages(); err != nil {
-			return nil, err
-		}
-		o.checkOutputValues()
-		return &o.result, nil
+		return o.replaceTextsToImages()
 	}
 
-	return nil, nil
+	return nil
 }
@@ -0,0 +1,90 @@
+package gdoctableapp
+
+import (
+	"reflect"
+	"testing"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+func TestChain(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}, {"c", "d"}})
+	b := &hookBackend{FakeBackend: f}
+	values := []ValueObject{{Values: [][]interface{}{{"x", "y"}}}}
+	values[0].Range.StartRowIndex = 1
+	_, err := New().Docs("doc").SetBackend(b).Chain(
+		New().SetCellStyle(HeaderHighlight("#ff0000")),
+		New().SetColumnWidths(&ColumnWidthsRequest{Widths: []ColumnWidth{{Column: 0, Width: 100}}}),
+		New().InsertRows(&InsertRowsRequest{Index: 0, Count: 1}),
+		New().SetValuesByObject(values),
+		New().DeleteRowsAndColumns(&DeleteRowsColumnsRequest{Rows: []int64{2}}),
+	).Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, testValues(t, f), [][]string{{"", ""}, {"x", "y"}})
+	// The requests until InsertRows are merged into one batchUpdate. The table is retrieved again only before
+	// SetValuesByObject and DeleteRowsAndColumns, because the indexes were shifted by the previous operations.
+	want := [][]string{
+		{"UpdateTableCellStyle", "UpdateTableColumnProperties", "InsertTableRow"},
+		{"DeleteContentRange", "InsertText", "DeleteContentRange", "InsertText"},
+		{"DeleteTableRow"},
+	}
+	var got [][]string
+	for _, requests := range b.requests {
+		got = append(got, requestNames(requests))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+	if b.gets != 3 {
+		t.Errorf("Document was retrieved %d times, want 3", b.gets)
+	}
+	table := testTable(t, f)
+	// The highlighted row was moved to the 2nd row by InsertRows.
+	if cs := table.TableRows[1].TableCells[0].TableCellStyle; cs == nil || cs.BackgroundColor == nil {
+		t.Error("style of the highlighted row was not kept")
+	}
+	if w := table.TableStyle.TableColumnProperties[0].Width; w == nil || w.Magnitude != 100 {
+		t.Errorf("width of column 0 = %v, want 100", w)
+	}
+}
+
+func TestChainWithoutShiftingIndexes(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}, {"c", "d"}})
+	b := &hookBackend{FakeBackend: f}
+	res, err := New().Docs("doc").SetBackend(b).Chain(
+		New().SetCellStyle(HeaderHighlight("#ff0000")),
+		New().PinHeaderRows(1),
+		New().GetValues(),
+	).Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, res.Values, [][]string{{"a", "b"}, {"c", "d"}})
+	if b.gets != 1 || len(b.requests) != 1 {
+		t.Fatalf("Document was retrieved %d times and batchUpdate was requested %d times, want 1 and 1", b.gets, len(b.requests))
+	}
+	if got, want := requestNames(b.requests[0]), []string{"UpdateTableCellStyle", "PinTableHeaderRows"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
+
+func TestShiftsIndexes(t *testing.T) {
+	for _, c := range []struct {
+		request *docs.Request
+		want    bool
+	}{
+		{&docs.Request{UpdateTableCellStyle: &docs.UpdateTableCellStyleRequest{}}, false},
+		{&docs.Request{UpdateTextStyle: &docs.UpdateTextStyleRequest{}}, false},
+		{&docs.Request{PinTableHeaderRows: &docs.PinTableHeaderRowsRequest{}}, false},
+		{&docs.Request{InsertText: &docs.InsertTextRequest{}}, true},
+		{&docs.Request{DeleteTableRow: &docs.DeleteTableRowRequest{}}, true},
+		{&docs.Request{MergeTableCells: &docs.MergeTableCellsRequest{}}, true},
+	} {
+		requests := []*docs.Request{{UpdateTableCellStyle: &docs.UpdateTableCellStyleRequest{}}, c.request}
+		if got := shiftsIndexes(requests); got != c.want {
+			t.Errorf("shiftsIndexes(%q) = %v, want %v", requestNames(requests), got, c.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package gdoctableapp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+
+	docs "google.golang.org/api/docs/v1"
+	drive "google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// hookBackend : FakeBackend which calls before and after around each batchUpdate.
+// When after returns an error, the error is returned although the requests were applied.
+// The requests of the applied batchUpdates are recorded in requests, the number of retrieving Document is recorded in gets,
+// and the number of the requests to Drive API is recorded in files.
+type hookBackend struct {
+	*FakeBackend
+	batches  int
+	gets     int
+	files    int
+	requests [][]*docs.Request
+	before   func(n int) error
+	after    func(n int) error
+}
+
+func (b *hookBackend) CreateFile(ctx context.Context, file *drive.File, media io.Reader) (*drive.File, error) {
+	b.files++
+	return b.FakeBackend.CreateFile(ctx, file, media)
+}
+
+func (b *hookBackend) CreatePermission(ctx context.Context, fileID string, permission *drive.Permission) (*drive.Permission, error) {
+	b.files++
+	return b.FakeBackend.CreatePermission(ctx, fileID, permission)
+}
+
+func (b *hookBackend) DeleteFile(ctx context.Context, fileID string) error {
+	b.files++
+	return b.FakeBackend.DeleteFile(ctx, fileID)
+}
+
+func (b *hookBackend) GetDocument(ctx context.Context, documentID string, fields ...googleapi.Field) (*docs.Document, error) {
+	b.gets++
+	return b.FakeBackend.GetDocument(ctx, documentID, fields...)
+}
+
+func (b *hookBackend) BatchUpdate(ctx context.Context, documentID string, req *docs.BatchUpdateDocumentRequest) (*docs.BatchUpdateDocumentResponse, error) {
+	b.batches++
+	if b.before != nil {
+		if err := b.before(b.batches); err != nil {
+			return nil, err
+		}
+	}
+	res, err := b.FakeBackend.BatchUpdate(ctx, documentID, req)
+	if err != nil {
+		return nil, err
+	}
+	b.requests = append(b.requests, req.Requests)
+	if b.after != nil {
+		if err := b.after(b.batches); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// touch : Modify the Document by others. Only the revision ID is changed.
+func touch(f *FakeBackend, documentID string) {
+	f.SetDocument(documentID, f.Document(documentID))
+}
+
+// newTestTable : Create the Document of "doc" including a table with values.
+func newTestTable(t *testing.T, values [][]interface{}) *FakeBackend {
+	t.Helper()
+	f := NewFakeBackend()
+	f.NewDocument("doc")
+	c := &CreateTableRequest{Rows: int64(len(values)), Columns: int64(len(values[0])), Index: 1, Values: values}
+	if _, err := New().Docs("doc").SetBackend(f).CreateTable(c).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// testValues : Retrieve the values of the 1st table of "doc".
+func testValues(t *testing.T, b Backend) [][]string {
+	t.Helper()
+	res, err := New().Docs("doc").SetBackend(b).GetValues().Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Values
+}
+
+// testTable : Return the 1st table of "doc".
+func testTable(t *testing.T, f *FakeBackend) *docs.Table {
+	t.Helper()
+	for _, e := range f.Document("doc").Body.Content {
+		if e.Table != nil {
+			return e.Table
+		}
+	}
+	t.Fatal("table was not found")
+	return nil
+}
+
+// assertDryRun : Check that the requests planned by DryRun are the same as the requests applied by p.
+// p is run with the backend of the Document created by newTable.
+func assertDryRun(t *testing.T, newTable func() *FakeBackend, p func() *Params) *FakeBackend {
+	t.Helper()
+	dry, err := p().SetBackend(newTable()).DryRun(true).Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := newTable()
+	b := &hookBackend{FakeBackend: f}
+	if _, err := p().SetBackend(b).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	var planned [][]*docs.Request
+	for _, e := range dry.Requests {
+		planned = append(planned, e.Requests)
+	}
+	if len(b.requests) == 0 {
+		t.Error("no requests were applied")
+	}
+	if !reflect.DeepEqual(planned, b.requests) {
+		t.Errorf("requests of DryRun are different from the applied requests:\n%s\n%s", requestsJSON(planned), requestsJSON(b.requests))
+	}
+	return f
+}
+
+// requestNames : Return the names of the requests like "InsertTableRow".
+func requestNames(requests []*docs.Request) []string {
+	var res []string
+	for _, r := range requests {
+		v := reflect.ValueOf(r).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.Kind() == reflect.Ptr && !f.IsNil() {
+				res = append(res, v.Type().Field(i).Name)
+			}
+		}
+	}
+	return res
+}
+
+func requestsJSON(requests [][]*docs.Request) string {
+	j, _ := json.Marshal(requests)
+	return string(j)
+}
+
+func assertValues(t *testing.T, got, want [][]string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("values = %q, want %q", got, want)
+	}
+}
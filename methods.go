@@ -4,9 +4,9 @@
 package gdoctableapp
 
 import (
+	"context"
+	"errors"
 	"net/http"
-
-	docs "google.golang.org/api/docs/v1"
 )
 
 ///
@@ -67,6 +67,60 @@ func (p *Params) AppendRow(c *AppendRowRequest) *Params {
 	return p
 }
 
+// InsertRows : Insert rows and values above or below any row of existing table.
+func (p *Params) InsertRows(r *InsertRowsRequest) *Params {
+	p.Works.DoInsertRows = true
+	p.InsertRowsRequest = r
+	return p
+}
+
+// InsertColumns : Insert columns and values left or right of any column of existing table.
+func (p *Params) InsertColumns(c *InsertColumnsRequest) *Params {
+	p.Works.DoInsertColumns = true
+	p.InsertColumnsRequest = c
+	return p
+}
+
+// MoveRow : Move the row of from to the row of to. The texts and styles of cells are kept.
+// For example, MoveRow(3, 0) moves the 4th row to the top of the table.
+func (p *Params) MoveRow(from, to int64) *Params {
+	p.Works.DoMoveRow = true
+	p.MoveP.From = from
+	p.MoveP.To = to
+	return p
+}
+
+// MoveColumn : Move the column of from to the column of to. The texts and styles of cells are kept.
+func (p *Params) MoveColumn(from, to int64) *Params {
+	p.Works.DoMoveColumn = true
+	p.MoveP.From = from
+	p.MoveP.To = to
+	return p
+}
+
+// SortRows : Sort the rows of a table by the sort keys. Only the cells whose values are changed are rewritten.
+func (p *Params) SortRows(s *SortRowsRequest) *Params {
+	p.Works.DoSortRows = true
+	p.SortRowsRequest = s
+	return p
+}
+
+// UpsertRows : Update the rows matched by the key column, and append the unmatched rows to the table.
+// The numbers of the updated, inserted and unchanged rows are returned in Result.UpsertResult.
+func (p *Params) UpsertRows(u *UpsertRowsRequest) *Params {
+	p.Works.DoUpsertRows = true
+	p.UpsertRowsRequest = u
+	return p
+}
+
+// SyncTable : Synchronize a table with values. Only the cells whose values are different are rewritten,
+// so the styles of the unchanged cells are kept. The rows and columns are appended and deleted to match the values.
+func (p *Params) SyncTable(values [][]string) *Params {
+	p.Works.DoSyncTable = true
+	p.SyncTableP.Values = values
+	return p
+}
+
 // CreateTable : Create new table with values.
 func (p *Params) CreateTable(c *CreateTableRequest) *Params {
 	p.Works.DoCreateTable = true
@@ -81,6 +135,76 @@ func (p *Params) DeleteRowsAndColumns(d *DeleteRowsColumnsRequest) *Params {
 	return p
 }
 
+// ClearRange : Clear all contents of the cells from (startRow, startCol) to (endRow, endCol) of a table.
+// endRow and endCol are not included. The texts, inline images and nested tables are deleted, and the table grid is kept.
+func (p *Params) ClearRange(startRow, startCol, endRow, endCol int64) *Params {
+	p.Works.DoClearRange = true
+	p.ClearRangeP.StartRowIndex = startRow
+	p.ClearRangeP.StartColumnIndex = startCol
+	p.ClearRangeP.EndRowIndex = endRow
+	p.ClearRangeP.EndColumnIndex = endCol
+	return p
+}
+
+// ClearTable : Clear all contents of the cells of a table. The table grid is kept.
+func (p *Params) ClearTable() *Params {
+	p.Works.DoClearTable = true
+	return p
+}
+
+// DeleteRowsWhere : Delete the rows of a table for which match returns true.
+// match is called with the values of each row. The matched rows are deleted by one batchUpdate.
+// The indexes of the deleted rows are returned in Result.DeletedRows.
+func (p *Params) DeleteRowsWhere(match func(row []string) bool) *Params {
+	p.Works.DoDeleteRowsWhere = true
+	p.DeleteRowsWhereP.Match = match
+	return p
+}
+
+// DeleteRowsByValue : Delete the rows of a table whose value of column is value.
+func (p *Params) DeleteRowsByValue(column int64, value string) *Params {
+	return p.DeleteRowsWhere(func(row []string) bool {
+		return column >= 0 && column < int64(len(row)) && row[column] == value
+	})
+}
+
+// SetCellStyle : Set the background colors, borders, padding and content alignment of cells of a table.
+// HeaderHighlight and StripedRows can be used as the presets.
+func (p *Params) SetCellStyle(c *CellStyleRequest) *Params {
+	p.Works.DoCellStyle = true
+	p.CellStyleRequest = c
+	return p
+}
+
+// SetColumnWidths : Set the fixed or evenly distributed widths of columns of a table.
+func (p *Params) SetColumnWidths(c *ColumnWidthsRequest) *Params {
+	p.Works.DoColumnWidths = true
+	p.ColumnWidthsRequest = c
+	return p
+}
+
+// PinHeaderRows : Pin the first n rows of a table as the header rows. The header rows are repeated on each page.
+// When n is 0, the header rows are unpinned.
+func (p *Params) PinHeaderRows(n int64) *Params {
+	p.Works.DoPinHeaderRows = true
+	p.PinnedHeaderRows = n
+	return p
+}
+
+// MergeCells : Merge cells of a table. Each range is merged into one cell.
+func (p *Params) MergeCells(m *MergeCellsRequest) *Params {
+	p.Works.DoMergeCells = true
+	p.MergeCellsRequest = m
+	return p
+}
+
+// UnmergeCells : Unmerge the merged cells in the ranges of a table.
+func (p *Params) UnmergeCells(m *MergeCellsRequest) *Params {
+	p.Works.DoUnmergeCells = true
+	p.MergeCellsRequest = m
+	return p
+}
+
 // DeleteTable : Delete table.
 func (p *Params) DeleteTable() *Params {
 	p.Works.DoDeleteTable = true
@@ -107,12 +231,73 @@ func (p *Params) GetValues() *Params {
 	return p
 }
 
+// GetValuesInto : Retrieve values from a table of Google Document and store them into dst.
+// dst is a pointer to a slice of structs. The first row of the table is used as the header row,
+// and the header is mapped to the fields by the tag of `gdoc:"Column Name"`. Please see UnmarshalValues.
+//
+// sample:
+//
+//	type Row struct {
+//		ID      int       `gdoc:"ID"`
+//		Name    string    `gdoc:"Name"`
+//		Done    bool      `gdoc:"Done"`
+//		Created time.Time `gdoc:"Created,layout=2006-01-02"`
+//	}
+//	var rows []Row
+//	res, err := g.Docs(documentID).TableIndex(0).GetValuesInto(&rows).Do(client)
+func (p *Params) GetValuesInto(dst interface{}) *Params {
+	p.Works.DoGetValues = true
+	p.ValuesInto = dst
+	return p
+}
+
+// GetCells : Retrieve the rich contents of cells from a table of Google Document.
+// The text runs with the styles, the inline objects and the nested tables are returned in Result.Cells.
+func (p *Params) GetCells() *Params {
+	p.Works.DoGetCells = true
+	return p
+}
+
 // GetTables : Retrieve all tables from Google Document.
 func (p *Params) GetTables() *Params {
 	p.Works.DoGetTables = true
 	return p
 }
 
+// Chain : Run several methods for the table in order by one call.
+// Each operation is created by New() with one method. The Document ID and the table index of the parent are used for all operations.
+// The requests of the operations are merged into one batchUpdate as long as the indexes of the table are not shifted.
+//
+// sample:
+//
+//	ops := []*gdoctableapp.Params{
+//		gdoctableapp.New().AppendRow(appendRowRequest),
+//		gdoctableapp.New().DeleteRowsAndColumns(deleteRowsColumnsRequest),
+//		gdoctableapp.New().ReplaceTextsToImagesByURL(searchText, replaceImageURL).TableOnly(true),
+//	}
+//	res, err := g.Docs(documentID).TableIndex(tableIndex).Chain(ops...).Do(client)
+func (p *Params) Chain(ops ...*Params) *Params {
+	p.Operations = append(p.Operations, ops...)
+	return p
+}
+
+// SetFormatter : Set the formatter for converting the values of all columns to the strings of cells.
+// NumberFormatter, DateFormatter and BoolFormatter can be used. Also, your own function can be used.
+func (p *Params) SetFormatter(f Formatter) *Params {
+	p.FormatterP.Table = f
+	return p
+}
+
+// SetColumnFormatter : Set the formatter for the column. The formatter of the column is used instead of SetFormatter.
+// column is the column index of the table. The start number of index is 0.
+func (p *Params) SetColumnFormatter(column int64, f Formatter) *Params {
+	if p.FormatterP.Columns == nil {
+		p.FormatterP.Columns = map[int64]Formatter{}
+	}
+	p.FormatterP.Columns[column] = f
+	return p
+}
+
 ///
 /// Required parameters
 ///
@@ -123,6 +308,46 @@ func (p *Params) TableIndex(tableIndex int) *Params {
 	return p
 }
 
+// TableByHeader : Select the table whose first row starts with header instead of TableIndex.
+// When no table or several tables match, an error is returned.
+func (p *Params) TableByHeader(header []string) *Params {
+	p.TableSelectorP.By = selectByHeader
+	p.TableSelectorP.Header = header
+	return p
+}
+
+// TableByFirstCell : Select the table whose first cell is text instead of TableIndex.
+// When no table or several tables match, an error is returned.
+func (p *Params) TableByFirstCell(text string) *Params {
+	p.TableSelectorP.By = selectByFirstCell
+	p.TableSelectorP.FirstCell = text
+	return p
+}
+
+// TableUnderHeading : Select the first table under the heading of text instead of TableIndex.
+// level is the level of heading from 1 to 6. When level is 0, the heading of any level is used.
+// The table is searched until the next heading of the same or higher level.
+func (p *Params) TableUnderHeading(text string, level int) *Params {
+	p.TableSelectorP.By = selectByHeading
+	p.TableSelectorP.Heading = text
+	p.TableSelectorP.HeadingLevel = level
+	return p
+}
+
+// TableUnderHeadingID : Select the first table under the heading of headingID (e.g. "h.xxxxxxxx") instead of TableIndex.
+func (p *Params) TableUnderHeadingID(headingID string) *Params {
+	p.TableSelectorP.By = selectByHeadingID
+	p.TableSelectorP.HeadingID = headingID
+	return p
+}
+
+// TableInNamedRange : Select the first table which includes or follows the start of the named range instead of TableIndex.
+func (p *Params) TableInNamedRange(name string) *Params {
+	p.TableSelectorP.By = selectByNamedRange
+	p.TableSelectorP.NamedRange = name
+	return p
+}
+
 // Docs : Set Document ID
 func (p *Params) Docs(documentID string) *Params {
 	p.DocumentID = documentID
@@ -135,31 +360,85 @@ func (p *Params) ShowAPIResponse(f bool) *Params {
 	return p
 }
 
+// Retry : Set the retry policy for the requests to Docs API and Drive API.
+// When the request fails with the status code of the policy, the request is retried with the exponential backoff.
+// DefaultRetryPolicy() can be used for the quota errors and the server errors.
+func (p *Params) Retry(policy *RetryPolicy) *Params {
+	p.RetryPolicy = policy
+	return p
+}
+
+// RetryOnConflict : Set the number of retries when *ConflictError occurs.
+// When the Document was modified by others after it was retrieved, the method is run again from retrieving the Document.
+func (p *Params) RetryOnConflict(retries int) *Params {
+	p.ConflictRetries = retries
+	return p
+}
+
+// DryRun : Build the requests for Docs API without running the method of batchUpdate.
+// The request bodies are returned in Result.Requests. Documents.Get is still used for retrieving the table.
+// When ReplaceTextsToImagesByFile is used, the image file is not uploaded and the file path is used as the URL of image.
+func (p *Params) DryRun(f bool) *Params {
+	p.DryRunFlag = f
+	return p
+}
+
+// SetBackend : Set the backend for the requests to Docs API and Drive API.
+// When FakeBackend is set, the methods can be used without Google account. In this case, client of Do can be nil.
+func (p *Params) SetBackend(b Backend) *Params {
+	p.Backend = b
+	return p
+}
+
 // init : Initialize
 func (o *obj) init() error {
-	srv, err := docs.New(o.params.Client)
+	if o.params.Backend != nil {
+		o.backend = o.params.Backend
+		return nil
+	}
+	b, err := newAPIBackend(o.params.Client)
 	if err != nil {
 		return err
 	}
-	o.srv = srv
+	o.backend = b
 	return nil
 }
 
 // Do : Retrieve all file list and folder tree under root.
 func (p *Params) Do(client *http.Client) (*Result, error) {
-	o := &obj{
-		params: *p,
-		fields: defaultFields,
-	}
-	o.params.Client = client
-	if err := o.init(); err != nil {
-		return nil, err
+	return p.DoContext(context.Background(), client)
+}
+
+// DoContext : Run the method with the context. The context is used for all requests to Docs API and Drive API.
+// When the context is canceled or its deadline is exceeded, *CanceledError is returned.
+// When the request to APIs fails, *APIError is returned.
+// When the Document was modified by others after it was retrieved, *ConflictError is returned.
+// When ctx is nil, context.Background() is used.
+func (p *Params) DoContext(ctx context.Context, client *http.Client) (*Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
 	}
-	res, err := o.handler()
-	if err != nil {
-		return nil, err
+	for retry := 0; ; retry++ {
+		o := &obj{
+			params: *p,
+			fields: defaultFields,
+			ctx:    ctx,
+		}
+		o.params.Client = client
+		if err := o.init(); err != nil {
+			return nil, err
+		}
+		res, err := o.handler()
+		if err != nil {
+			var conflict *ConflictError
+			// When a batchUpdate was already applied, the method cannot be run again from the start.
+			if errors.As(err, &conflict) && !o.applied && retry < p.ConflictRetries {
+				continue
+			}
+			return nil, err
+		}
+		return res, nil
 	}
-	return res, nil
 }
 
 // New : Create an object for using gdoctableapp
@@ -0,0 +1,197 @@
+package gdoctableapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestRetryOnConflictBeforeApplied(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}})
+	b := &hookBackend{FakeBackend: f, before: func(n int) error {
+		if n == 1 {
+			touch(f, "doc")
+		}
+		return nil
+	}}
+	_, err := New().Docs("doc").SetBackend(b).AppendRow(&AppendRowRequest{Values: [][]interface{}{{"c", "d"}}}).RetryOnConflict(1).Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, testValues(t, f), [][]string{{"a", "b"}, {"c", "d"}})
+}
+
+func TestRetryOnConflictAfterApplied(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}})
+	// The row is inserted by the 1st batchUpdate, and the values are put by the 2nd batchUpdate.
+	b := &hookBackend{FakeBackend: f, before: func(n int) error {
+		if n == 2 {
+			touch(f, "doc")
+		}
+		return nil
+	}}
+	_, err := New().Docs("doc").SetBackend(b).AppendRow(&AppendRowRequest{Values: [][]interface{}{{"c", "d"}}}).RetryOnConflict(3).Do(nil)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("err = %v, want ConflictError", err)
+	}
+	if b.batches != 2 {
+		t.Errorf("batchUpdate was requested %d times, want 2", b.batches)
+	}
+	assertValues(t, testValues(t, f), [][]string{{"a", "b"}, {"", ""}})
+}
+
+func TestReplaceTextsToImagesByFileDeletesFileOnError(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"IMG", "b"}})
+	b := &hookBackend{FakeBackend: f, before: func(n int) error {
+		touch(f, "doc")
+		return nil
+	}}
+	_, err := New().Docs("doc").SetBackend(b).ReplaceTextsToImagesByFile("IMG", "helpers_test.go").RetryOnConflict(1).Do(nil)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("err = %v, want ConflictError", err)
+	}
+	if ids := f.FileIDs(); len(ids) != 0 {
+		t.Errorf("files %v were not deleted", ids)
+	}
+}
+
+func TestDoContextCanceled(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := New().Docs("doc").SetBackend(f).GetValues().DoContext(ctx, nil)
+	var canceled *CanceledError
+	if !errors.As(err, &canceled) || canceled.Op != "Documents.Get" {
+		t.Fatalf("err = %v, want CanceledError of Documents.Get", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestDoContextDeadlineDuringRetry(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}})
+	b := &hookBackend{FakeBackend: f, before: applyThenFail}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	policy := testRetryPolicy()
+	policy.InitialBackoff = time.Hour
+	_, err := New().Docs("doc").SetBackend(b).SetValuesBy2DArray([][]interface{}{{"c"}}).Retry(policy).DoContext(ctx, nil)
+	var canceled *CanceledError
+	if !errors.As(err, &canceled) || canceled.Op != "Documents.BatchUpdate" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want CanceledError of Documents.BatchUpdate with context.DeadlineExceeded", err)
+	}
+	assertValues(t, testValues(t, f), [][]string{{"a", "b"}})
+}
+
+func TestDoContextWithNilContext(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}})
+	res, err := New().Docs("doc").SetBackend(f).GetValues().DoContext(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, res.Values, [][]string{{"a", "b"}})
+	_, err = New().Docs("unknown").SetBackend(f).GetValues().DoContext(nil, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want APIError", err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	f := NewFakeBackend()
+	_, err := New().Docs("unknown").SetBackend(f).GetValues().Do(nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Op != "Documents.Get" || apiErr.Code != http.StatusNotFound {
+		t.Fatalf("err = %v, want APIError of Documents.Get with 404", err)
+	}
+	var gErr *googleapi.Error
+	if !errors.As(err, &gErr) {
+		t.Errorf("err = %v, want googleapi.Error in the chain", err)
+	}
+}
+
+func TestDryRunDoesNotModifyDocument(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		p    *Params
+	}{
+		{"SetValuesBy2DArray", New().SetValuesBy2DArray([][]interface{}{{"c", "d", "e"}, {"f"}})},
+		{"AppendRow", New().AppendRow(&AppendRowRequest{Values: [][]interface{}{{"c", "d"}}})},
+		{"DeleteRowsAndColumns", New().DeleteRowsAndColumns(&DeleteRowsColumnsRequest{Columns: []int64{1}})},
+		{"ReplaceTextsToImagesByFile", New().ReplaceTextsToImagesByFile("a", "helpers_test.go")},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			f := newTestTable(t, [][]interface{}{{"a", "b"}})
+			before := f.Document("doc")
+			b := &hookBackend{FakeBackend: f}
+			res, err := c.p.Docs("doc").SetBackend(b).DryRun(true).Do(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Requests) == 0 {
+				t.Error("no requests were returned")
+			}
+			if b.batches != 0 || b.files != 0 {
+				t.Errorf("batchUpdate was requested %d times and Drive API was requested %d times, want 0", b.batches, b.files)
+			}
+			if ids := f.FileIDs(); len(ids) != 0 {
+				t.Errorf("files %v were uploaded", ids)
+			}
+			if after := f.Document("doc"); after.RevisionId != before.RevisionId {
+				t.Errorf("revision ID was changed from %s to %s", before.RevisionId, after.RevisionId)
+			}
+		})
+	}
+}
+
+func TestDryRunReplaceTextsToImagesByFile(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}})
+	res, err := New().Docs("doc").SetBackend(f).ReplaceTextsToImagesByFile("a", "helpers_test.go").DryRun(true).Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The file path is used as the URL of image, because the file is not uploaded.
+	var uris []string
+	for _, r := range res.Requests[0].Requests {
+		if r.InsertInlineImage != nil {
+			uris = append(uris, r.InsertInlineImage.Uri)
+		}
+	}
+	if len(uris) != 1 || uris[0] != "helpers_test.go" {
+		t.Errorf("URIs of images = %q, want [helpers_test.go]", uris)
+	}
+}
+
+func TestDryRunCreateTableAndAppendRow(t *testing.T) {
+	newDocument := func() *FakeBackend {
+		f := NewFakeBackend()
+		f.NewDocument("doc")
+		if err := fakeApply(f, insertText(1, "日本🍣\n")); err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	f := assertDryRun(t, newDocument, func() *Params {
+		return New().Docs("doc").CreateTable(&CreateTableRequest{Rows: 2, Columns: 2, Index: 5, Values: [][]interface{}{{"a", "🍣"}, {"", "b"}}})
+	})
+	assertValues(t, testValues(t, f), [][]string{{"a", "🍣"}, {"", "b"}})
+
+	newTable := func() *FakeBackend {
+		return newTestTable(t, [][]interface{}{{"a1", "日本🍣"}, {"a2", ""}})
+	}
+	f = assertDryRun(t, newTable, func() *Params {
+		return New().Docs("doc").AppendRow(&AppendRowRequest{Values: [][]interface{}{{"x", "🍣y"}, {"", "z"}}})
+	})
+	assertValues(t, testValues(t, f), [][]string{{"a1", "日本🍣"}, {"a2", ""}, {"x", "🍣y"}, {"", "z"}})
+	f = assertDryRun(t, newTable, func() *Params {
+		return New().Docs("doc").SetValuesBy2DArray([][]interface{}{{"x", "y", "🍣"}, nil, {"", "", "z"}})
+	})
+	assertValues(t, testValues(t, f), [][]string{{"x", "y", "🍣"}, {"a2", "", ""}, {"", "", "z"}})
+}
@@ -0,0 +1,290 @@
+// Package gdoctableapp (move.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the methods for moving rows and columns.
+package gdoctableapp
+
+import (
+	"fmt"
+	"strings"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// cellTextRun : Text run of a cell. offset is the offset from the start of the cell text in UTF-16 code units.
+type cellTextRun struct {
+	offset int64
+	length int64
+	style  *docs.TextStyle
+}
+
+// moveRow : Move a row of the table to the position of to.
+// An empty row is inserted at the position, the texts and styles of the cells are copied to it, and the source row is deleted.
+// These requests are run by one batchUpdate.
+func (o *obj) moveRow() error {
+	from, to := o.params.MoveP.From, o.params.MoveP.To
+	table := o.docTable.Table
+	if from < 0 || from >= table.Rows || to < 0 || to >= table.Rows {
+		return fmt.Errorf("Rows for moving are outside of the table")
+	}
+	if from == to {
+		return nil
+	}
+	if err := o.checkMovable(); err != nil {
+		return err
+	}
+	ref := table.TableRows[to]
+	rowStart := ref.StartIndex
+	if to > from {
+		rowStart = ref.EndIndex
+	}
+	br := &docs.BatchUpdateDocumentRequest{}
+	br.Requests = append(br.Requests, &docs.Request{
+		InsertTableRow: &docs.InsertTableRowRequest{
+			TableCellLocation: &docs.TableCellLocation{
+				TableStartLocation: &docs.Location{Index: o.docTable.StartIndex},
+				RowIndex:           to,
+			},
+			InsertBelow: to > from,
+		},
+	})
+	cells := table.TableRows[from].TableCells
+	newRow := to
+	if to > from {
+		newRow = to + 1
+	}
+	for j := len(cells) - 1; j >= 0; j-- {
+		// Each cell of the inserted empty row is a cell marker and a newline.
+		index := rowStart + 2 + 2*int64(j)
+		requests, err := createCopyCellRequests(cells[j], index)
+		if err != nil {
+			return fmt.Errorf("Cell (%d, %d) cannot be moved: %v", from, j, err)
+		}
+		br.Requests = append(br.Requests, requests...)
+	}
+	for j, cell := range cells {
+		if r := o.createCopyCellStyleRequest(cell, newRow, int64(j)); r != nil {
+			br.Requests = append(br.Requests, r)
+		}
+	}
+	deleteRow := from
+	if to < from {
+		deleteRow = from + 1
+	}
+	br.Requests = append(br.Requests, &docs.Request{
+		DeleteTableRow: &docs.DeleteTableRowRequest{
+			TableCellLocation: &docs.TableCellLocation{
+				TableStartLocation: &docs.Location{Index: o.docTable.StartIndex},
+				RowIndex:           deleteRow,
+			},
+		},
+	})
+	o.requestBody = br
+	if err := o.documentbatchUpdate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// moveColumn : Move a column of the table to the position of to.
+// An empty column is inserted at the position, the texts and styles of the cells are copied to it, and the source column is deleted.
+// These requests are run by one batchUpdate.
+func (o *obj) moveColumn() error {
+	from, to := o.params.MoveP.From, o.params.MoveP.To
+	table := o.docTable.Table
+	if from < 0 || from >= table.Columns || to < 0 || to >= table.Columns {
+		return fmt.Errorf("Columns for moving are outside of the table")
+	}
+	if from == to {
+		return nil
+	}
+	if err := o.checkMovable(); err != nil {
+		return err
+	}
+	br := &docs.BatchUpdateDocumentRequest{}
+	br.Requests = append(br.Requests, &docs.Request{
+		InsertTableColumn: &docs.InsertTableColumnRequest{
+			TableCellLocation: &docs.TableCellLocation{
+				TableStartLocation: &docs.Location{Index: o.docTable.StartIndex},
+				ColumnIndex:        to,
+			},
+			InsertRight: to > from,
+		},
+	})
+	newCol := to
+	if to > from {
+		newCol = to + 1
+	}
+	for i := len(table.TableRows) - 1; i >= 0; i-- {
+		row := table.TableRows[i]
+		cellStart := row.EndIndex
+		if newCol < int64(len(row.TableCells)) {
+			cellStart = row.TableCells[newCol].StartIndex
+		}
+		// The empty cells of the upper rows are inserted before this cell. Each of them is a cell marker and a newline.
+		index := cellStart + 2*int64(i) + 1
+		requests, err := createCopyCellRequests(row.TableCells[from], index)
+		if err != nil {
+			return fmt.Errorf("Cell (%d, %d) cannot be moved: %v", i, from, err)
+		}
+		br.Requests = append(br.Requests, requests...)
+	}
+	for i, row := range table.TableRows {
+		if r := o.createCopyCellStyleRequest(row.TableCells[from], int64(i), newCol); r != nil {
+			br.Requests = append(br.Requests, r)
+		}
+	}
+	if ts := table.TableStyle; ts != nil && from < int64(len(ts.TableColumnProperties)) {
+		if props := ts.TableColumnProperties[from]; props != nil && props.WidthType == "FIXED_WIDTH" && props.Width != nil {
+			br.Requests = append(br.Requests, &docs.Request{
+				UpdateTableColumnProperties: &docs.UpdateTableColumnPropertiesRequest{
+					TableStartLocation:    &docs.Location{Index: o.docTable.StartIndex},
+					ColumnIndices:         []int64{newCol},
+					TableColumnProperties: props,
+					Fields:                "width,widthType",
+				},
+			})
+		}
+	}
+	deleteCol := from
+	if to < from {
+		deleteCol = from + 1
+	}
+	br.Requests = append(br.Requests, &docs.Request{
+		DeleteTableColumn: &docs.DeleteTableColumnRequest{
+			TableCellLocation: &docs.TableCellLocation{
+				TableStartLocation: &docs.Location{Index: o.docTable.StartIndex},
+				ColumnIndex:        deleteCol,
+			},
+		},
+	})
+	o.requestBody = br
+	if err := o.documentbatchUpdate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkMovable : Check whether the cells of the table can be moved.
+func (o *obj) checkMovable() error {
+	o.parseTable()
+	if len(o.mergedCells) > 0 {
+		return fmt.Errorf("Rows and columns of the table including the merged cells cannot be moved")
+	}
+	return nil
+}
+
+// cellText : Return the text of the cell without the last newline and the text runs of it.
+// When the cell includes the contents except for texts, an error is returned.
+func cellText(cell *docs.TableCell) (string, []cellTextRun, error) {
+	var text strings.Builder
+	var runs []cellTextRun
+	var offset int64
+	for _, e := range cell.Content {
+		if e.Paragraph == nil {
+			return "", nil, fmt.Errorf("the cell includes the content except for paragraphs")
+		}
+		for _, pe := range e.Paragraph.Elements {
+			if pe.TextRun == nil {
+				return "", nil, fmt.Errorf("the cell includes the content except for texts")
+			}
+			length := utf16Len(pe.TextRun.Content)
+			runs = append(runs, cellTextRun{offset: offset, length: length, style: pe.TextRun.TextStyle})
+			text.WriteString(pe.TextRun.Content)
+			offset += length
+		}
+	}
+	res := strings.TrimSuffix(text.String(), "\n")
+	if n := len(runs); n > 0 && res != text.String() {
+		runs[n-1].length--
+		if runs[n-1].length == 0 {
+			runs = runs[:n-1]
+		}
+	}
+	return res, runs, nil
+}
+
+// createCopyCellRequests : Create the requests for copying the text and the text styles of cell to the empty cell of index.
+func createCopyCellRequests(cell *docs.TableCell, index int64) ([]*docs.Request, error) {
+	text, runs, err := cellText(cell)
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return nil, nil
+	}
+	requests := []*docs.Request{
+		{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{Index: index},
+				Text:     text,
+			},
+		},
+	}
+	for _, r := range runs {
+		if r.style == nil || r.length == 0 {
+			continue
+		}
+		requests = append(requests, createUpdateTextStyleRequest(index+r.offset, index+r.offset+r.length, r.style, "*"))
+	}
+	return requests, nil
+}
+
+// createCopyCellStyleRequest : Create UpdateTableCellStyleRequest for copying the style of cell to the cell of row and col.
+// Only the fields which are set to the style are copied. When there are no fields, nil is returned.
+func (o *obj) createCopyCellStyleRequest(cell *docs.TableCell, row, col int64) *docs.Request {
+	cs := cell.TableCellStyle
+	if cs == nil {
+		return nil
+	}
+	var fields []string
+	if cs.BackgroundColor != nil {
+		fields = append(fields, "backgroundColor")
+	}
+	if cs.BorderTop != nil {
+		fields = append(fields, "borderTop")
+	}
+	if cs.BorderBottom != nil {
+		fields = append(fields, "borderBottom")
+	}
+	if cs.BorderLeft != nil {
+		fields = append(fields, "borderLeft")
+	}
+	if cs.BorderRight != nil {
+		fields = append(fields, "borderRight")
+	}
+	if cs.PaddingTop != nil {
+		fields = append(fields, "paddingTop")
+	}
+	if cs.PaddingBottom != nil {
+		fields = append(fields, "paddingBottom")
+	}
+	if cs.PaddingLeft != nil {
+		fields = append(fields, "paddingLeft")
+	}
+	if cs.PaddingRight != nil {
+		fields = append(fields, "paddingRight")
+	}
+	if cs.ContentAlignment != "" {
+		fields = append(fields, "contentAlignment")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	style := *cs
+	style.RowSpan, style.ColumnSpan = 0, 0
+	return &docs.Request{
+		UpdateTableCellStyle: &docs.UpdateTableCellStyleRequest{
+			TableRange: &docs.TableRange{
+				TableCellLocation: &docs.TableCellLocation{
+					TableStartLocation: &docs.Location{Index: o.docTable.StartIndex},
+					RowIndex:           row,
+					ColumnIndex:        col,
+				},
+				RowSpan:    1,
+				ColumnSpan: 1,
+			},
+			TableCellStyle: &style,
+			Fields:         strings.Join(fields, ","),
+		},
+	}
+}
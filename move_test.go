@@ -0,0 +1,175 @@
+package gdoctableapp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// newMoveTestTable : Create a table of 4 rows and 3 columns for moving rows and columns.
+// The cells of the 2nd column are bold, and the first 2 characters of the cells of the 3rd column are italic.
+// The 1st cell of each row except for the last row has a background color, and the columns have the fixed widths.
+func newMoveTestTable(t *testing.T) *FakeBackend {
+	t.Helper()
+	values := [][]interface{}{}
+	for i := 0; i < 4; i++ {
+		values = append(values, []interface{}{
+			fmt.Sprintf("r%dc0", i),
+			StyledValue{Value: fmt.Sprintf("r%dc1", i), Style: &TextStyle{Bold: Bool(true)}},
+			fmt.Sprintf("r%dc2", i),
+		})
+	}
+	f := newTestTable(t, values)
+	for _, row := range testTable(t, f).TableRows {
+		start := row.TableCells[2].Content[0].StartIndex
+		if err := fakeApply(f, createUpdateTextStyleRequest(start, start+2, &docs.TextStyle{Italic: true}, "italic")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cellStyle := &CellStyleRequest{}
+	for i, color := range []string{"#ff0000", "#00ff00", "#0000ff"} {
+		cellStyle.Ranges = append(cellStyle.Ranges, CellStyleRange{StartRowIndex: int64(i), Rows: 1, Columns: 1, Style: &CellStyle{BackgroundColor: color}})
+	}
+	widths := &ColumnWidthsRequest{Widths: []ColumnWidth{{Column: 0, Width: 100}, {Column: 1, Width: 150}, {Column: 2, Width: 200}}}
+	if _, err := New().Docs("doc").SetBackend(f).SetCellStyle(cellStyle).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New().Docs("doc").SetBackend(f).SetColumnWidths(widths).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// cellSignatures : Return the texts and styles of the cells of the table.
+// The bold texts are enclosed by "*", the italic texts are enclosed by "_", and the background color is added like "[1 0 0]".
+func cellSignatures(table *docs.Table) [][]string {
+	var res [][]string
+	for _, row := range table.TableRows {
+		var r []string
+		for _, cell := range row.TableCells {
+			var s strings.Builder
+			for _, e := range cell.Content {
+				for _, pe := range e.Paragraph.Elements {
+					text := strings.TrimSuffix(pe.TextRun.Content, "\n")
+					if text == "" {
+						continue
+					}
+					if ts := pe.TextRun.TextStyle; ts != nil && ts.Bold {
+						text = "*" + text + "*"
+					}
+					if ts := pe.TextRun.TextStyle; ts != nil && ts.Italic {
+						text = "_" + text + "_"
+					}
+					s.WriteString(text)
+				}
+			}
+			if cs := cell.TableCellStyle; cs != nil && cs.BackgroundColor != nil && cs.BackgroundColor.Color != nil {
+				c := cs.BackgroundColor.Color.RgbColor
+				fmt.Fprintf(&s, "[%g %g %g]", c.Red, c.Green, c.Blue)
+			}
+			r = append(r, s.String())
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// columnWidths : Return the widths of the columns of the table.
+func columnWidths(table *docs.Table) []float64 {
+	var res []float64
+	for _, p := range table.TableStyle.TableColumnProperties {
+		res = append(res, p.Width.Magnitude)
+	}
+	return res
+}
+
+// moveItem : Return the copy of s in which the element of from is moved to the position of to.
+func moveItem(s []string, from, to int64) []string {
+	res := append([]string{}, s[:from]...)
+	res = append(res, s[from+1:]...)
+	res = append(res[:to], append([]string{s[from]}, res[to:]...)...)
+	return res
+}
+
+func TestMoveRow(t *testing.T) {
+	initial := cellSignatures(testTable(t, newMoveTestTable(t)))
+	if want := []string{"r0c0[1 0 0]", "*r0c1*", "_r0_c2"}; !reflect.DeepEqual(initial[0], want) {
+		t.Fatalf("1st row = %q, want %q", initial[0], want)
+	}
+	for _, c := range []struct {
+		name     string
+		from, to int64
+	}{
+		{"first to last", 0, 3},
+		{"last to first", 3, 0},
+		{"down", 1, 2},
+		{"up", 2, 1},
+		{"down to next", 0, 1},
+		{"up to previous", 3, 2},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			f := newMoveTestTable(t)
+			if _, err := New().Docs("doc").SetBackend(f).MoveRow(c.from, c.to).Do(nil); err != nil {
+				t.Fatal(err)
+			}
+			order := moveItem([]string{"0", "1", "2", "3"}, c.from, c.to)
+			var want [][]string
+			for _, i := range order {
+				want = append(want, initial[i[0]-'0'])
+			}
+			table := testTable(t, f)
+			if got := cellSignatures(table); !reflect.DeepEqual(got, want) {
+				t.Errorf("cells = %q, want %q", got, want)
+			}
+			if got, want := columnWidths(table), []float64{100, 150, 200}; !reflect.DeepEqual(got, want) {
+				t.Errorf("column widths = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMoveColumn(t *testing.T) {
+	initial := cellSignatures(testTable(t, newMoveTestTable(t)))
+	widths := []string{"100", "150", "200"}
+	for _, c := range []struct {
+		name     string
+		from, to int64
+	}{
+		{"first to last", 0, 2},
+		{"last to first", 2, 0},
+		{"right", 0, 1},
+		{"left", 1, 0},
+		{"middle to last", 1, 2},
+		{"last to middle", 2, 1},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			f := newMoveTestTable(t)
+			if _, err := New().Docs("doc").SetBackend(f).MoveColumn(c.from, c.to).Do(nil); err != nil {
+				t.Fatal(err)
+			}
+			var want [][]string
+			for _, row := range initial {
+				want = append(want, moveItem(row, c.from, c.to))
+			}
+			table := testTable(t, f)
+			if got := cellSignatures(table); !reflect.DeepEqual(got, want) {
+				t.Errorf("cells = %q, want %q", got, want)
+			}
+			if got, want := fmt.Sprint(columnWidths(table)), fmt.Sprint(moveItem(widths, c.from, c.to)); got != want {
+				t.Errorf("column widths = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestMoveRowOutsideOfTable(t *testing.T) {
+	f := newMoveTestTable(t)
+	for _, p := range []*Params{New().MoveRow(0, 4), New().MoveRow(-1, 0), New().MoveColumn(3, 0)} {
+		if _, err := p.Docs("doc").SetBackend(f).Do(nil); err == nil {
+			t.Error("no error for the position outside of the table")
+		}
+	}
+}
@@ -3,19 +3,24 @@
 package gdoctableapp
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
+	"unicode/utf16"
 
 	docs "google.golang.org/api/docs/v1"
 	drive "google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 )
 
 // getTables : Retrieve all tables.
 func (o *obj) getTables() *obj {
+	current := o.docTable
+	defer func() { o.docTable = current }()
 	for i, table := range o.docTables {
 		o.docTable = table
 		o.parseTable()
@@ -23,17 +28,14 @@ func (o *obj) getTables() *obj {
 		for _, e := range o.contents {
 			temp1 := []string{}
 			for _, f := range e {
-				temp2 := []string{}
-				for _, g := range f.tempColsContent {
-					temp2 = append(temp2, strings.Replace(g.content, "\n", "", -1))
-				}
-				temp1 = append(temp1, strings.Join(temp2, "\n"))
+				temp1 = append(temp1, f.value())
 			}
 			res = append(res, temp1)
 		}
 		t := &Table{}
 		t.Index = int64(i)
 		t.Values = res
+		t.MergedCells = o.mergedCells
 		t.TablePosition.StartIndex = table.StartIndex
 		t.TablePosition.EndIndex = table.EndIndex
 		o.result.Tables = append(o.result.Tables, *t)
@@ -41,6 +43,16 @@ func (o *obj) getTables() *obj {
 	return o
 }
 
+// value : Return the value of the cell. The contents are concatenated, and the last newline of the cell is removed.
+// The text of a paragraph is split into several text runs by the styles, so the text runs must not be joined with "\n".
+func (c *tempColsContents) value() string {
+	var value strings.Builder
+	for _, g := range c.tempColsContent {
+		value.WriteString(g.content)
+	}
+	return strings.TrimSuffix(value.String(), "\n")
+}
+
 // getValues : Retrieve values from a table of Document.
 func (o *obj) getValues() ([][]string, error) {
 	o.parseTable()
@@ -48,11 +60,7 @@ func (o *obj) getValues() ([][]string, error) {
 	for _, e := range o.contents {
 		temp1 := []string{}
 		for _, f := range e {
-			temp2 := []string{}
-			for _, g := range f.tempColsContent {
-				temp2 = append(temp2, strings.Replace(g.content, "\n", "", -1))
-			}
-			temp1 = append(temp1, strings.Join(temp2, "\n"))
+			temp1 = append(temp1, f.value())
 		}
 		res = append(res, temp1)
 	}
@@ -116,22 +124,20 @@ func (o *obj) deleteRowsColumns() error {
 	if len(o.params.DeleteRowsColumnsRequest.Rows) == 0 && len(o.params.DeleteRowsColumnsRequest.Columns) == 0 {
 		return fmt.Errorf("No parameters for using DeleteRowsAndColumns()")
 	}
-	rows := o.params.DeleteRowsColumnsRequest.Rows
-	cols := o.params.DeleteRowsColumnsRequest.Columns
-	sort.Slice(rows, func(i, j int) bool { return rows[i] > rows[j] })
-	sort.Slice(cols, func(i, j int) bool { return cols[i] > cols[j] })
-	maxDeleteRow := rows[0]
-	maxDeleteCol := cols[0]
 	table := o.docTable.Table
-	if table.Rows < maxDeleteRow || table.Columns < maxDeleteCol {
-		return fmt.Errorf("Rows and columns for deleting are outside of the table")
+	rows, err := deleteIndexes(o.params.DeleteRowsColumnsRequest.Rows, table.Rows)
+	if err != nil {
+		return err
+	}
+	cols, err := deleteIndexes(o.params.DeleteRowsColumnsRequest.Columns, table.Columns)
+	if err != nil {
+		return err
 	}
-	inputObj := o.params.DeleteRowsColumnsRequest
 	l := &docs.Location{}
 	l.Index = o.docTable.StartIndex
 	br := &docs.BatchUpdateDocumentRequest{}
-	if len(inputObj.Rows) > 0 {
-		for _, e := range inputObj.Rows {
+	if len(rows) > 0 {
+		for _, e := range rows {
 			tc := &docs.TableCellLocation{}
 			tc.TableStartLocation = l
 			tc.RowIndex = e
@@ -143,8 +149,8 @@ func (o *obj) deleteRowsColumns() error {
 			br.Requests = append(br.Requests, dr)
 		}
 	}
-	if len(inputObj.Columns) > 0 {
-		for _, e := range inputObj.Columns {
+	if len(cols) > 0 {
+		for _, e := range cols {
 			tc := &docs.TableCellLocation{}
 			tc.TableStartLocation = l
 			tc.ColumnIndex = e
@@ -163,6 +169,127 @@ func (o *obj) deleteRowsColumns() error {
 	return nil
 }
 
+// deleteIndexes : Return the indexes for deleting sorted in descending order. The duplicated indexes are removed.
+// The inputted slice is not modified. When an index is outside of 0 to size-1, an error is returned.
+func deleteIndexes(indexes []int64, size int64) ([]int64, error) {
+	res := []int64{}
+	for _, e := range indexes {
+		if e < 0 || e >= size {
+			return nil, fmt.Errorf("Rows and columns for deleting are outside of the table")
+		}
+		res = append(res, e)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] > res[j] })
+	n := 0
+	for i, e := range res {
+		if i == 0 || e != res[n-1] {
+			res[n] = e
+			n++
+		}
+	}
+	return res[:n], nil
+}
+
+// deleteRowsWhere : Delete the rows matched by the function of DeleteRowsWhereP.
+// The indexes of the rows are resolved from the values of the table, and the rows are deleted by deleteRowsColumns.
+func (o *obj) deleteRowsWhere() error {
+	match := o.params.DeleteRowsWhereP.Match
+	if match == nil {
+		return fmt.Errorf("No function for using DeleteRowsWhere()")
+	}
+	values, err := o.getValues()
+	if err != nil {
+		return err
+	}
+	var rows []int64
+	for i, row := range values {
+		if match(row) {
+			rows = append(rows, int64(i))
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	if int64(len(rows)) == o.docTable.Table.Rows {
+		return fmt.Errorf("All rows of the table are matched. When you want to delete the table, please use DeleteTable()")
+	}
+	o.result.DeletedRows = rows
+	o.params.DeleteRowsColumnsRequest = &DeleteRowsColumnsRequest{Rows: rows}
+	return o.deleteRowsColumns()
+}
+
+// mergeCells : Merge or unmerge cells of a table.
+func (o *obj) mergeCells(unmerge bool) error {
+	if o.params.MergeCellsRequest == nil || len(o.params.MergeCellsRequest.Ranges) == 0 {
+		return fmt.Errorf("No parameters for using MergeCells() and UnmergeCells()")
+	}
+	br := &docs.BatchUpdateDocumentRequest{}
+	for _, r := range o.params.MergeCellsRequest.Ranges {
+		tableRange, err := o.createTableRange(r)
+		if err != nil {
+			return err
+		}
+		dr := &docs.Request{}
+		if unmerge {
+			dr.UnmergeTableCells = &docs.UnmergeTableCellsRequest{TableRange: tableRange}
+		} else {
+			if tableRange.RowSpan == 1 && tableRange.ColumnSpan == 1 {
+				return fmt.Errorf("Range for merging is only one cell")
+			}
+			dr.MergeTableCells = &docs.MergeTableCellsRequest{TableRange: tableRange}
+		}
+		br.Requests = append(br.Requests, dr)
+	}
+	o.requestBody = br
+	if err := o.documentbatchUpdate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// createTableRange : Create TableRange of the range for the table.
+func (o *obj) createTableRange(r CellRange) (*docs.TableRange, error) {
+	table := o.docTable.Table
+	rows, cols := r.Rows, r.Columns
+	if rows == 0 {
+		rows = table.Rows - r.StartRowIndex
+	}
+	if cols == 0 {
+		cols = table.Columns - r.StartColumnIndex
+	}
+	if r.StartRowIndex < 0 || r.StartColumnIndex < 0 || rows <= 0 || cols <= 0 ||
+		r.StartRowIndex+rows > table.Rows || r.StartColumnIndex+cols > table.Columns {
+		return nil, fmt.Errorf("Range of cells is outside of the table")
+	}
+	return &docs.TableRange{
+		TableCellLocation: &docs.TableCellLocation{
+			TableStartLocation: &docs.Location{
+				Index: o.docTable.StartIndex,
+			},
+			RowIndex:    r.StartRowIndex,
+			ColumnIndex: r.StartColumnIndex,
+		},
+		RowSpan:    rows,
+		ColumnSpan: cols,
+	}, nil
+}
+
+// checkMergedCells : Check whether the values are put to the cells merged into other cells.
+func (o *obj) checkMergedCells() error {
+	for _, v := range o.parsedValues {
+		for _, m := range o.mergedCells {
+			if v.row >= m.Row && v.row < m.Row+m.RowSpan && v.col >= m.Column && v.col < m.Column+m.ColumnSpan &&
+				(v.row != m.Row || v.col != m.Column) {
+				if v.content == "" {
+					break
+				}
+				return fmt.Errorf("Cell (%d, %d) is merged into cell (%d, %d)", v.row, v.col, m.Row, m.Column)
+			}
+		}
+	}
+	return nil
+}
+
 // setValuesMain : Main method for setValues.
 func (o *obj) setValuesMain() error {
 	dupChk, err := o.checkDupValues()
@@ -173,16 +300,21 @@ func (o *obj) setValuesMain() error {
 		return fmt.Errorf("Range of inputted values are duplicated")
 	}
 	o.parseInputValuesForSetValues(dupChk)
-	o.addRowsAndColumnsForSetValues()
+	maxRow, maxCol := o.addRowsAndColumnsForSetValues()
 	if o.requestBody != nil {
 		if err := o.documentbatchUpdate(); err != nil {
 			return err
 		}
-		if err := o.getTable(); err != nil {
+		if o.params.DryRunFlag {
+			o.docTable = expandTable(o.docTable, maxRow, maxCol)
+		} else if err := o.getTable(); err != nil {
 			return err
 		}
 	}
 	o.parseTable()
+	if err := o.checkMergedCells(); err != nil {
+		return err
+	}
 	o.createSetValuesRequests()
 	if err := o.documentbatchUpdate(); err != nil {
 		return err
@@ -277,6 +409,9 @@ func (o *obj) createSetValuesRequests() {
 				InsertText: t,
 			}
 			br.Requests = append(br.Requests, dr)
+			if o.parsedValues[i].style != nil {
+				br.Requests = append(br.Requests, createUpdateTextStyleRequest(location.Index, location.Index+utf16Len(v), o.parsedValues[i].style, o.parsedValues[i].fields))
+			}
 		}
 	}
 	o.requestBody = br
@@ -291,6 +426,17 @@ func (o *obj) crateTable() error {
 	if rows == 0 || columns == 0 {
 		return fmt.Errorf("Values of Rows and/or Columns are not found")
 	}
+	if o.params.CreateTableRequest.PinnedHeaderRows < 0 || o.params.CreateTableRequest.PinnedHeaderRows > rows {
+		return fmt.Errorf("PinnedHeaderRows must be from 0 to Rows")
+	}
+	if int64(len(o.params.CreateTableRequest.ColumnWidths)) > columns {
+		return fmt.Errorf("Number of ColumnWidths is over the columns of the table")
+	}
+	for _, w := range o.params.CreateTableRequest.ColumnWidths {
+		if err := checkColumnWidth(w); err != nil {
+			return err
+		}
+	}
 	table.Rows = rows
 	table.Columns = columns
 	var idx int64
@@ -312,13 +458,18 @@ func (o *obj) crateTable() error {
 		if err != nil {
 			return err
 		}
-		for i := len(contents) - 1; i >= 0; i-- {
-			if table := contents[i].Table; table != nil {
-				o.docTable = contents[i]
-				break
+		if o.params.DryRunFlag {
+			// The table is not created by DryRun. The new table is put before the last newline of the body.
+			idx = contents[len(contents)-1].EndIndex - 1
+		} else {
+			for i := len(contents) - 1; i >= 0; i-- {
+				if table := contents[i].Table; table != nil {
+					o.docTable = contents[i]
+					break
+				}
 			}
+			idx = o.docTable.StartIndex - 1
 		}
-		idx = o.docTable.StartIndex - 1
 	} else if o.params.CreateTableRequest.Index != 0 {
 		loc := &docs.Location{}
 		loc.Index = o.params.CreateTableRequest.Index
@@ -333,7 +484,7 @@ func (o *obj) crateTable() error {
 	}
 
 	if len(o.params.CreateTableRequest.Values) > 0 {
-		val, err := parseInputValues(
+		val, err := o.parseInputValues(
 			o.params.CreateTableRequest.Values,
 			idx,
 			o.params.CreateTableRequest.Rows,
@@ -355,10 +506,27 @@ func (o *obj) crateTable() error {
 					InsertText: t,
 				}
 				br.Requests = append(br.Requests, dr2)
+				if val[i].style != nil {
+					br.Requests = append(br.Requests, createUpdateTextStyleRequest(val[i].index, val[i].index+utf16Len(v), val[i].style, val[i].fields))
+				}
 			}
 		}
 	}
-	if o.params.CreateTableRequest.Index > 0 || len(o.params.CreateTableRequest.Values) > 0 {
+	if widths := o.params.CreateTableRequest.ColumnWidths; len(widths) > 0 {
+		var cw []ColumnWidth
+		for i, w := range widths {
+			cw = append(cw, ColumnWidth{Column: int64(i), Width: w})
+		}
+		requests, err := createColumnWidthRequests(idx+1, cw)
+		if err != nil {
+			return err
+		}
+		br.Requests = append(br.Requests, requests...)
+	}
+	if n := o.params.CreateTableRequest.PinnedHeaderRows; n > 0 {
+		br.Requests = append(br.Requests, createPinTableHeaderRowsRequest(idx+1, n))
+	}
+	if len(br.Requests) > 0 {
 		o.requestBody = br
 		if err := o.documentbatchUpdate(); err != nil {
 			return err
@@ -383,6 +551,120 @@ func (o *obj) appendRow() error {
 	return nil
 }
 
+// insertRows : Insert rows with values.
+func (o *obj) insertRows() error {
+	r := o.params.InsertRowsRequest
+	if r == nil {
+		return fmt.Errorf("No parameters for using InsertRows()")
+	}
+	count := r.Count
+	if count == 0 {
+		count = int64(len(r.Values))
+	}
+	if count <= 0 {
+		return fmt.Errorf("Number of rows for inserting is not set")
+	}
+	if int64(len(r.Values)) > count {
+		return fmt.Errorf("Values are over the inserted rows")
+	}
+	if r.Index < 0 || r.Index >= o.docTable.Table.Rows {
+		return fmt.Errorf("Row index %d is outside of the table", r.Index)
+	}
+	br := &docs.BatchUpdateDocumentRequest{}
+	for i := int64(0); i < count; i++ {
+		br.Requests = append(br.Requests, &docs.Request{
+			InsertTableRow: &docs.InsertTableRowRequest{
+				TableCellLocation: &docs.TableCellLocation{
+					TableStartLocation: &docs.Location{
+						Index: o.docTable.StartIndex,
+					},
+					RowIndex: r.Index,
+				},
+				InsertBelow: r.InsertBelow,
+			},
+		})
+	}
+	start := r.Index
+	if r.InsertBelow {
+		start++
+	}
+	return o.insertRowsColumnsMain(br, start, count, 0, 0, r.Values)
+}
+
+// insertColumns : Insert columns with values.
+func (o *obj) insertColumns() error {
+	c := o.params.InsertColumnsRequest
+	if c == nil {
+		return fmt.Errorf("No parameters for using InsertColumns()")
+	}
+	var maxCol int64
+	for _, row := range c.Values {
+		if maxCol < int64(len(row)) {
+			maxCol = int64(len(row))
+		}
+	}
+	count := c.Count
+	if count == 0 {
+		count = maxCol
+	}
+	if count <= 0 {
+		return fmt.Errorf("Number of columns for inserting is not set")
+	}
+	if maxCol > count {
+		return fmt.Errorf("Values are over the inserted columns")
+	}
+	if c.Index < 0 || c.Index >= o.docTable.Table.Columns {
+		return fmt.Errorf("Column index %d is outside of the table", c.Index)
+	}
+	br := &docs.BatchUpdateDocumentRequest{}
+	for i := int64(0); i < count; i++ {
+		br.Requests = append(br.Requests, &docs.Request{
+			InsertTableColumn: &docs.InsertTableColumnRequest{
+				TableCellLocation: &docs.TableCellLocation{
+					TableStartLocation: &docs.Location{
+						Index: o.docTable.StartIndex,
+					},
+					ColumnIndex: c.Index,
+				},
+				InsertRight: c.InsertRight,
+			},
+		})
+	}
+	start := c.Index
+	if c.InsertRight {
+		start++
+	}
+	return o.insertRowsColumnsMain(br, 0, 0, start, count, c.Values)
+}
+
+// insertRowsColumnsMain : Insert rows or columns by br, and put values from the start of the inserted rows or columns.
+func (o *obj) insertRowsColumnsMain(br *docs.BatchUpdateDocumentRequest, rowAt, rows, colAt, cols int64, values [][]interface{}) error {
+	o.requestBody = br
+	if err := o.documentbatchUpdate(); err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	if o.params.DryRunFlag {
+		o.docTable = expandTableAt(o.docTable, rowAt, rows, colAt, cols)
+	} else if err := o.getTable(); err != nil {
+		return err
+	}
+	vo := &ValueObject{}
+	vo.Values = values
+	if rows > 0 {
+		vo.Range.StartRowIndex = rowAt
+	} else {
+		vo.Range.StartColumnIndex = colAt
+	}
+	o.params.ValuesObject = []ValueObject{*vo}
+	if err := o.setValuesMain(); err != nil {
+		return err
+	}
+	return nil
+}
+
 // appendBrForInsertInlineImage : Append request to slice.
 func (o *obj) appendBrForInsertInlineImage(br *docs.BatchUpdateDocumentRequest, startIndex, endIndex int64) {
 	br.Requests = append(br.Requests, createDeleteContentRangeRequest(startIndex, endIndex))
@@ -394,6 +676,18 @@ func (o *obj) appendBrForInsertInlineImage(br *docs.BatchUpdateDocumentRequest,
 	))
 }
 
+// deleteUploadedFile : Delete the file uploaded by uploadImageFile. When no file was uploaded, nothing is done.
+func (o *obj) deleteUploadedFile() error {
+	id := o.params.ReplaceTextsToImagesP.FileID
+	if id == "" {
+		return nil
+	}
+	o.params.ReplaceTextsToImagesP.FileID = ""
+	return o.call("Files.Delete", func() error {
+		return o.backend.DeleteFile(o.ctx, id)
+	})
+}
+
 // getTextRunContent : Get textrun content.
 func (o *obj) getTextRunContent(ar *[]docs.ParagraphElement, h *docs.StructuralElement) {
 	if h.Paragraph != nil {
@@ -427,16 +721,19 @@ func (o *obj) uploadImageFile() error {
 		return err
 	}
 	defer imgFile.Close()
-	if err := o.getSrvForDrive(); err != nil {
-		return err
-	}
 	f := &drive.File{
 		Name: filepath.Base(o.params.ReplaceTextsToImagesP.ReplaceToImage) + "_From_gdoctableapp",
 	}
-	file, err := o.srvDrive.Files.Create(f).Media(imgFile).Fields("id,webContentLink").Do()
+	var file *drive.File
+	// Files.Create is not retried, because the file created by the failed attempt would be left in Google Drive.
+	err = o.callOnce("Files.Create", func() (err error) {
+		file, err = o.backend.CreateFile(o.ctx, f, imgFile)
+		return err
+	})
 	if err != nil {
 		return err
 	}
+	o.applied = true
 	o.result.ResponseFromAPIs = append(o.result.ResponseFromAPIs, file)
 	o.params.ReplaceTextsToImagesP.FileID = file.Id
 	o.params.ReplaceTextsToImagesP.ReplaceToImage = file.WebContentLink
@@ -444,27 +741,30 @@ func (o *obj) uploadImageFile() error {
 		Type: "anyone",
 		Role: "reader",
 	}
-	resPermissions, err := o.srvDrive.Permissions.Create(file.Id, permissiondata).Do()
-	if err != nil {
+	var resPermissions *drive.Permission
+	err = o.call("Permissions.Create", func() (err error) {
+		resPermissions, err = o.backend.CreatePermission(o.ctx, file.Id, permissiondata)
 		return err
-	}
-	o.result.ResponseFromAPIs = append(o.result.ResponseFromAPIs, resPermissions)
-	return nil
-}
-
-// getSrvForDrive : Get service for using Drive API.
-func (o *obj) getSrvForDrive() error {
-	srv, err := drive.New(o.params.Client)
+	})
 	if err != nil {
 		return err
 	}
-	o.srvDrive = srv
+	o.result.ResponseFromAPIs = append(o.result.ResponseFromAPIs, resPermissions)
 	return nil
 }
 
 // replaceTextsToImages : Replace texts to images by URL.
-func (o *obj) replaceTextsToImages() error {
-	if o.params.Works.DoReplaceTextsToImagesByFile {
+func (o *obj) replaceTextsToImages() (err error) {
+	if o.params.ReplaceTextsToImagesP.ReplaceFromText == "" {
+		return fmt.Errorf("Text for replacing is empty")
+	}
+	if o.params.Works.DoReplaceTextsToImagesByFile && !o.params.DryRunFlag {
+		// The uploaded file is deleted even when the texts cannot be replaced, because it is not used any more.
+		defer func() {
+			if dErr := o.deleteUploadedFile(); err == nil {
+				err = dErr
+			}
+		}()
 		if err := o.uploadImageFile(); err != nil {
 			return err
 		}
@@ -483,16 +783,24 @@ func (o *obj) replaceTextsToImages() error {
 		}
 	}
 	br := &docs.BatchUpdateDocumentRequest{}
+	searchText := o.params.ReplaceTextsToImagesP.ReplaceFromText
 	for i := int64(len(ar)) - 1; i >= 0; i-- {
 		e := ar[i]
 		content := e.TextRun.Content
-		searchText := o.params.ReplaceTextsToImagesP.ReplaceFromText
-		if strings.TrimSpace(content) == searchText {
-			offset := len(content) - len(strings.TrimSpace(content))
-			o.appendBrForInsertInlineImage(br, e.StartIndex, e.EndIndex-int64(offset))
-		} else {
-			start := e.StartIndex + int64(strings.Index(content, searchText))
-			o.appendBrForInsertInlineImage(br, start, start+int64(len(searchText)))
+		var offsets []int
+		for offset := 0; ; {
+			n := strings.Index(content[offset:], searchText)
+			if n < 0 {
+				break
+			}
+			offsets = append(offsets, offset+n)
+			offset += n + len(searchText)
+		}
+		// The texts are replaced from the last one, because the image shifts the indexes of the following texts.
+		for j := len(offsets) - 1; j >= 0; j-- {
+			// The indexes of Docs API are counted in UTF-16 code units. So the byte offset of Go is converted.
+			start := e.StartIndex + utf16Len(content[:offsets[j]])
+			o.appendBrForInsertInlineImage(br, start, start+utf16Len(searchText))
 		}
 	}
 	if len(br.Requests) > 0 {
@@ -500,11 +808,8 @@ func (o *obj) replaceTextsToImages() error {
 		if err := o.documentbatchUpdate(); err != nil {
 			return err
 		}
-		if o.params.Works.DoReplaceTextsToImagesByFile {
-			err := o.srvDrive.Files.Delete(o.params.ReplaceTextsToImagesP.FileID).Do()
-			if err != nil {
-				return err
-			}
+		if err := o.flushRequests(); err != nil {
+			return err
 		}
 	} else {
 		o.result.Message = fmt.Sprintf("'%s' was not found.", o.params.ReplaceTextsToImagesP.ReplaceFromText)
@@ -512,36 +817,27 @@ func (o *obj) replaceTextsToImages() error {
 	return nil
 }
 
-// convertStr : Convert value to string.
-func convertStr(v interface{}) (string, error) {
-	switch value := v.(type) {
-	case int:
-		if g, ok := v.(int); ok {
-			return fmt.Sprint(g), nil
-		}
-		return "", fmt.Errorf("error")
-	case int64:
-		if g, ok := v.(int64); ok {
-			return fmt.Sprint(g), nil
-		}
-		return "", fmt.Errorf("error")
-	case float64:
-		if g, ok := v.(float64); ok {
-			return fmt.Sprint(g), nil
-		}
-		return "", fmt.Errorf("error")
-	case string:
-		if g, ok := v.(string); ok {
-			return g, nil
-		}
-		return "", fmt.Errorf("error")
-	default:
-		return "", fmt.Errorf("error: Unknown value: %+v, %+v", v, value)
+// utf16Len : Length of the string in UTF-16 code units. The indexes of Docs API are counted in UTF-16 code units.
+func utf16Len(s string) int64 {
+	return int64(len(utf16.Encode([]rune(s))))
+}
+
+// utf16Split : Split the string at the offset counted in UTF-16 code units.
+func utf16Split(s string, offset int64) (string, string) {
+	u := utf16.Encode([]rune(s))
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > int64(len(u)) {
+		offset = int64(len(u))
 	}
+	return string(utf16.Decode(u[:offset])), string(utf16.Decode(u[offset:]))
 }
 
 // parseInputValues : Parse input values for 2 dimensional array.
-func parseInputValues(values [][]interface{}, index, rows, cols int64) ([]tempCheckDupValues, error) {
+// index is the location of the new table. The indexes of the cells are those of the empty table,
+// because the values are inserted from the last cell and the lengths of values don't shift the indexes of the previous cells.
+func (o *obj) parseInputValues(values [][]interface{}, index, rows, cols int64) ([]tempCheckDupValues, error) {
 	index += 4
 	v := []tempCheckDupValues{}
 	var maxCol int64
@@ -554,7 +850,7 @@ func parseInputValues(values [][]interface{}, index, rows, cols int64) ([]tempCh
 		}
 		for col := int64(0); col < cols; col++ {
 			if maxRow > row && maxCol > col && values[row][col] != "" {
-				colVal, err := convertStr(values[row][col])
+				colVal, style, fields, err := o.parseValue(values[row][col], col, nil)
 				if err != nil {
 					return nil, err
 				}
@@ -563,6 +859,8 @@ func parseInputValues(values [][]interface{}, index, rows, cols int64) ([]tempCh
 					col:     int64(col),
 					content: colVal,
 					index:   index,
+					style:   style,
+					fields:  fields,
 				}
 				v = append(v, *temp)
 			}
@@ -574,7 +872,8 @@ func parseInputValues(values [][]interface{}, index, rows, cols int64) ([]tempCh
 }
 
 // addRowsAndColumnsForSetValues : Create requests for adding rows and columns for the inputted values.
-func (o *obj) addRowsAndColumnsForSetValues() {
+// The maximum row and column of the inputted values are returned.
+func (o *obj) addRowsAndColumnsForSetValues() (int64, int64) {
 	values := o.params.ValuesObject
 	var maxRow, maxCol int64
 	for _, e := range values {
@@ -596,6 +895,127 @@ func (o *obj) addRowsAndColumnsForSetValues() {
 		}
 	}
 	o.createInsertTableRowColumnRequest(maxRow, maxCol)
+	return maxRow, maxCol
+}
+
+// expandTable : Create the table expanded to rows and columns with the indexes after the rows and columns were inserted.
+// This is used for DryRun, because the table cannot be retrieved again.
+func expandTable(e *docs.StructuralElement, rows, cols int64) *docs.StructuralElement {
+	var addRows, addCols int64
+	if rows > e.Table.Rows {
+		addRows = rows - e.Table.Rows
+	}
+	if cols > e.Table.Columns {
+		addCols = cols - e.Table.Columns
+	}
+	return expandTableAt(e, e.Table.Rows, addRows, e.Table.Columns, addCols)
+}
+
+// expandTableAt : Create the table that the empty rows are inserted at rowAt and the empty columns are inserted at colAt.
+// This is used for DryRun, because the table cannot be retrieved again.
+func expandTableAt(e *docs.StructuralElement, rowAt, addRows, colAt, addCols int64) *docs.StructuralElement {
+	rows := e.Table.Rows + addRows
+	cols := e.Table.Columns + addCols
+	t := &docs.Table{
+		Rows:    rows,
+		Columns: cols,
+	}
+	source := func(i, at, n int64) int64 {
+		if i < at {
+			return i
+		}
+		if i < at+n {
+			return -1
+		}
+		return i - n
+	}
+	index := e.StartIndex + 1
+	for i := int64(0); i < rows; i++ {
+		row := &docs.TableRow{StartIndex: index}
+		index++
+		si := source(i, rowAt, addRows)
+		for j := int64(0); j < cols; j++ {
+			cell := &docs.TableCell{StartIndex: index}
+			sj := source(j, colAt, addCols)
+			if si >= 0 && sj >= 0 && si < int64(len(e.Table.TableRows)) && sj < int64(len(e.Table.TableRows[si].TableCells)) {
+				src := e.Table.TableRows[si].TableCells[sj]
+				cell.Content = shiftContent(src.Content, cell.StartIndex-src.StartIndex)
+				cell.TableCellStyle = src.TableCellStyle
+				index += src.EndIndex - src.StartIndex
+			} else {
+				cell.Content = []*docs.StructuralElement{createEmptyParagraph(index + 1)}
+				index += 2
+			}
+			cell.EndIndex = index
+			row.TableCells = append(row.TableCells, cell)
+		}
+		row.EndIndex = index
+		t.TableRows = append(t.TableRows, row)
+	}
+	return &docs.StructuralElement{
+		StartIndex: e.StartIndex,
+		EndIndex:   index + 1,
+		Table:      t,
+	}
+}
+
+// createEmptyParagraph : Create an empty paragraph at index.
+func createEmptyParagraph(index int64) *docs.StructuralElement {
+	return &docs.StructuralElement{
+		StartIndex: index,
+		EndIndex:   index + 1,
+		Paragraph: &docs.Paragraph{
+			Elements: []*docs.ParagraphElement{
+				{
+					StartIndex: index,
+					EndIndex:   index + 1,
+					TextRun:    &docs.TextRun{Content: "\n"},
+				},
+			},
+		},
+	}
+}
+
+// shiftContent : Copy the content with the indexes shifted by delta.
+func shiftContent(content []*docs.StructuralElement, delta int64) []*docs.StructuralElement {
+	var res []*docs.StructuralElement
+	for _, e := range content {
+		c := *e
+		c.StartIndex += delta
+		c.EndIndex += delta
+		if e.Paragraph != nil {
+			p := *e.Paragraph
+			p.Elements = nil
+			for _, f := range e.Paragraph.Elements {
+				pe := *f
+				pe.StartIndex += delta
+				pe.EndIndex += delta
+				p.Elements = append(p.Elements, &pe)
+			}
+			c.Paragraph = &p
+		}
+		if e.Table != nil {
+			t := *e.Table
+			t.TableRows = nil
+			for _, f := range e.Table.TableRows {
+				r := *f
+				r.StartIndex += delta
+				r.EndIndex += delta
+				r.TableCells = nil
+				for _, g := range f.TableCells {
+					tc := *g
+					tc.StartIndex += delta
+					tc.EndIndex += delta
+					tc.Content = shiftContent(g.Content, delta)
+					r.TableCells = append(r.TableCells, &tc)
+				}
+				t.TableRows = append(t.TableRows, &r)
+			}
+			c.Table = &t
+		}
+		res = append(res, &c)
+	}
+	return res
 }
 
 // parseInputValuesForSetValues : Sort the inputted values.
@@ -621,7 +1041,7 @@ func (o *obj) checkDupValues() (*dupCheck, error) {
 		for i, row := range e.Values {
 			temp2 := []tempCheckDupValues{}
 			for j, col := range row {
-				colVal, err := convertStr(col)
+				colVal, style, fields, err := o.parseValue(col, int64(j)+colOffset, e.Style)
 				if err != nil {
 					return nil, err
 				}
@@ -629,6 +1049,8 @@ func (o *obj) checkDupValues() (*dupCheck, error) {
 					row:     int64(i) + rowOffset,
 					col:     int64(j) + colOffset,
 					content: colVal,
+					style:   style,
+					fields:  fields,
 				}
 				temp2 = append(temp2, *t)
 			}
@@ -658,11 +1080,27 @@ func (o *obj) parseTable() {
 	tableRows := docContent.Table.TableRows
 	var rowsDelCell [][]*docs.Request
 	var rowsContents [][]*tempColsContents
+	var mergedCells []MergedCell
 	for i := 0; i < len(tableRows); i++ {
 		tableCells := tableRows[i].TableCells
 		var tRowsDelCell []*docs.Request
 		var tRowsContents []*tempColsContents
 		for j := 0; j < len(tableCells); j++ {
+			if style := tableCells[j].TableCellStyle; style != nil && (style.RowSpan > 1 || style.ColumnSpan > 1) {
+				m := MergedCell{
+					Row:        int64(i),
+					Column:     int64(j),
+					RowSpan:    style.RowSpan,
+					ColumnSpan: style.ColumnSpan,
+				}
+				if m.RowSpan < 1 {
+					m.RowSpan = 1
+				}
+				if m.ColumnSpan < 1 {
+					m.ColumnSpan = 1
+				}
+				mergedCells = append(mergedCells, m)
+			}
 			tColsContents := &tempColsContents{}
 			contents := tableCells[j].Content
 			var si int64
@@ -716,6 +1154,7 @@ func (o *obj) parseTable() {
 	}
 	o.delCell = rowsDelCell
 	o.contents = rowsContents
+	o.mergedCells = mergedCells
 	o.cell1stIndex = rowsContents[0][0].tempColsContent[0].startIndex
 }
 
@@ -735,10 +1174,18 @@ func (o *obj) getAllTables() error {
 
 // getTable : Retrieve table from Google Document.
 func (o *obj) getTable() error {
+	if s := o.params.TableSelectorP.By; (s == selectByHeading || s == selectByHeadingID) && o.fields == defaultFields {
+		o.fields = headingFields
+	}
 	contents, err := o.getDocument()
 	if err != nil {
 		return err
 	}
+	o.docTable = nil
+	o.tableStale = false
+	if o.params.TableSelectorP.By != "" {
+		return o.selectTable(contents)
+	}
 	c := 0
 	for _, e := range contents {
 		if table := e.Table; table != nil {
@@ -756,12 +1203,109 @@ func (o *obj) getTable() error {
 }
 
 // documentbatchUpdate : Request the method of batchUpdate for Google Document.
+// When the operations are chained, the requests are merged into the pending requests.
 func (o *obj) documentbatchUpdate() error {
+	if o.requestBody != nil && o.chain {
+		if o.pendingShift {
+			if err := o.flushRequests(); err != nil {
+				return err
+			}
+		}
+		if o.pendingBody == nil {
+			o.pendingBody = &docs.BatchUpdateDocumentRequest{}
+		}
+		o.pendingBody.Requests = append(o.pendingBody.Requests, o.requestBody.Requests...)
+		if shiftsIndexes(o.requestBody.Requests) {
+			o.pendingShift = true
+			o.tableStale = true
+		}
+		o.requestBody = nil
+		return nil
+	}
+	return o.sendBatchUpdate()
+}
+
+// flushRequests : Request the pending requests of the chained operations.
+func (o *obj) flushRequests() error {
+	if o.pendingBody == nil {
+		return nil
+	}
+	o.requestBody = o.pendingBody
+	o.pendingBody = nil
+	o.pendingShift = false
+	return o.sendBatchUpdate()
+}
+
+// shiftsIndexes : Check whether the requests shift the indexes of Document.
+func shiftsIndexes(requests []*docs.Request) bool {
+	for _, r := range requests {
+		switch {
+		case r.UpdateTextStyle != nil,
+			r.UpdateParagraphStyle != nil,
+			r.UpdateTableCellStyle != nil,
+			r.UpdateTableColumnProperties != nil,
+			r.UpdateTableRowStyle != nil,
+			r.PinTableHeaderRows != nil:
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// sendBatchUpdate : Request requestBody with the method of batchUpdate.
+func (o *obj) sendBatchUpdate() error {
+	if o.requestBody != nil && o.params.DryRunFlag {
+		o.result.Requests = append(o.result.Requests, o.requestBody)
+		o.requestBody = nil
+		return nil
+	}
 	if o.requestBody != nil {
-		doc, err := o.srv.Documents.BatchUpdate(o.params.DocumentID, o.requestBody).Do()
+		if o.revisionID != "" {
+			o.requestBody.WriteControl = &docs.WriteControl{
+				RequiredRevisionId: o.revisionID,
+			}
+		}
+		// Without RequiredRevisionId, the requests applied before the server error would be applied again by the retry.
+		call := o.call
+		if o.requestBody.WriteControl == nil {
+			call = o.callOnce
+		}
+		attempts := len(o.result.RetryAttempts)
+		var doc *docs.BatchUpdateDocumentResponse
+		var sent bool
+		var lastErr error
+		err := call("Documents.BatchUpdate", func() (err error) {
+			if sent {
+				// The failed attempt might be applied. When the revision ID was changed, the requests are not sent again.
+				d, err := o.backend.GetDocument(o.ctx, o.params.DocumentID, "revisionId")
+				if err != nil {
+					return err
+				}
+				if d.RevisionId != o.revisionID {
+					return fmt.Errorf("%w from %s to %s after the error: %v", errMaybeApplied, o.revisionID, d.RevisionId, lastErr)
+				}
+			}
+			sent = true
+			doc, err = o.backend.BatchUpdate(o.ctx, o.params.DocumentID, o.requestBody)
+			lastErr = err
+			return err
+		})
 		if err != nil {
+			// Only the batchUpdate with RequiredRevisionId can fail by the conflict.
+			if o.requestBody.WriteControl != nil && isConflict(err) {
+				err = &ConflictError{RevisionID: o.revisionID, Err: errors.Unwrap(err)}
+			}
+			if len(o.result.RetryAttempts) > attempts {
+				// The requests might be applied by the failed attempt.
+				o.applied = true
+			}
 			return err
 		}
+		if doc.WriteControl != nil && doc.WriteControl.RequiredRevisionId != "" {
+			o.revisionID = doc.WriteControl.RequiredRevisionId
+		}
+		o.applied = true
 		o.result.ResponseFromAPIs = append(o.result.ResponseFromAPIs, doc)
 		o.requestBody = nil
 	}
@@ -769,17 +1313,43 @@ func (o *obj) documentbatchUpdate() error {
 }
 
 // getDocument : Retrieve Document object from Google Document.
+// The pending requests of the chained operations are requested before the Document is retrieved.
 func (o *obj) getDocument() ([]*docs.StructuralElement, error) {
-	doc, err := o.srv.Documents.Get(o.params.DocumentID).Fields(o.fields).Do()
+	if err := o.flushRequests(); err != nil {
+		return nil, err
+	}
+	fields := []googleapi.Field{o.fields, "revisionId"}
+	if o.params.TableSelectorP.By == selectByNamedRange {
+		fields = append(fields, "namedRanges")
+	}
+	if o.withObjects {
+		fields = append(fields, "inlineObjects")
+	}
+	var doc *docs.Document
+	err := o.call("Documents.Get", func() (err error) {
+		doc, err = o.backend.GetDocument(o.ctx, o.params.DocumentID, fields...)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	o.revisionID = doc.RevisionId
+	o.namedRanges = doc.NamedRanges
+	o.inlineObjects = doc.InlineObjects
 	o.result.ResponseFromAPIs = append(o.result.ResponseFromAPIs, doc)
 	return doc.Body.Content, nil
 }
 
 // optionChecker : Check inputted options.
 func (o *obj) optionChecker() error {
+	if o.countWorks() == 1 {
+		return nil
+	}
+	return fmt.Errorf("There are many options for methods. Please use one method for one call")
+}
+
+// countWorks : Count the methods set to Works.
+func (o *obj) countWorks() int {
 	r := reflect.ValueOf(&o.params.Works).Elem()
 	rt := r.Type()
 	fl := 0
@@ -791,8 +1361,5 @@ func (o *obj) optionChecker() error {
 			fl++
 		}
 	}
-	if fl == 1 {
-		return nil
-	}
-	return fmt.Errorf("There are many options for methods. Please use one method for one call")
+	return fl
 }
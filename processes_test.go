@@ -0,0 +1,438 @@
+package gdoctableapp
+
+import (
+	"reflect"
+	"testing"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+func TestUTF16Len(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int64
+	}{
+		{"", 0},
+		{"sample", 6},
+		{"日本", 2},
+		{"🍣", 2},
+		{"日本🍣sample\n", 11},
+		{"é", 1},
+		{"👨‍👩‍👧", 8},
+	}
+	for _, tt := range tests {
+		if got := utf16Len(tt.s); got != tt.want {
+			t.Errorf("utf16Len(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestUTF16Split(t *testing.T) {
+	tests := []struct {
+		s           string
+		offset      int64
+		left, right string
+	}{
+		{"sample", 3, "sam", "ple"},
+		{"日本🍣sample", 2, "日本", "🍣sample"},
+		{"日本🍣sample", 4, "日本🍣", "sample"},
+		{"日本🍣sample", 0, "", "日本🍣sample"},
+		{"日本🍣sample", 10, "日本🍣sample", ""},
+		{"日本🍣sample", 11, "日本🍣sample", ""},
+		{"日本🍣sample", -1, "", "日本🍣sample"},
+		// The offset inside of a surrogate pair cannot be decoded.
+		{"🍣", 1, "�", "�"},
+	}
+	for _, tt := range tests {
+		left, right := utf16Split(tt.s, tt.offset)
+		if left != tt.left || right != tt.right {
+			t.Errorf("utf16Split(%q, %d) = %q, %q, want %q, %q", tt.s, tt.offset, left, right, tt.left, tt.right)
+		}
+	}
+}
+
+func TestReplaceTextsToImagesByURLWithMultiByteTexts(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a"}})
+	if err := fakeApply(f, insertText(1, "日本🍣sample🍣sample")); err != nil {
+		t.Fatal(err)
+	}
+	const url = "https://example.com/a.png"
+	if _, err := New().Docs("doc").SetBackend(f).ReplaceTextsToImagesByURL("sample", url).SetImageSize(50, 40).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	d := f.Document("doc")
+	// "日" and "本" are 1 index, and "🍣" is 2 indexes. So the images are put at 5 and 8.
+	// Both texts in one text run are replaced.
+	if got, want := fakeLayout(d.Body.Content[:2]), `S0-1 P1-10{1-5"日本🍣" 5-6"*" 6-8"🍣" 8-9"*" 9-10"\n"}`; got != want {
+		t.Errorf("layout = %s, want %s", got, want)
+	}
+	for _, i := range []int{1, 3} {
+		id := d.Body.Content[1].Paragraph.Elements[i].InlineObjectElement.InlineObjectId
+		eo := d.InlineObjects[id].InlineObjectProperties.EmbeddedObject
+		if eo.ImageProperties.ContentUri != url || eo.Size.Width.Magnitude != 50 || eo.Size.Height.Magnitude != 40 {
+			t.Errorf("image = %s %+v %+v", eo.ImageProperties.ContentUri, eo.Size.Width, eo.Size.Height)
+		}
+	}
+}
+
+func TestReplaceTextsToImagesByURLInTable(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"🍣sample🍣sample", "日本sample"}})
+	if _, err := New().Docs("doc").SetBackend(f).ReplaceTextsToImagesByURL("sample", "https://example.com/a.png").TableOnly(true).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	// All "sample" of each text run are replaced.
+	cells := testTable(t, f).TableRows[0].TableCells
+	if got, want := fakeLayout(cells[0].Content), `P5-12{5-7"🍣" 7-8"*" 8-10"🍣" 10-11"*" 11-12"\n"}`; got != want {
+		t.Errorf("layout of cell (0, 0) = %s, want %s", got, want)
+	}
+	if got, want := fakeLayout(cells[1].Content), `P13-17{13-15"日本" 15-16"*" 16-17"\n"}`; got != want {
+		t.Errorf("layout of cell (0, 1) = %s, want %s", got, want)
+	}
+}
+
+func TestInsertRowsAndColumns(t *testing.T) {
+	newTable := func() *FakeBackend {
+		return newTestTable(t, [][]interface{}{{"a1", "b1"}, {"a2", "日本🍣"}})
+	}
+	tests := []struct {
+		name string
+		p    func() *Params
+		want [][]string
+	}{
+		{
+			name: "rows above",
+			p: func() *Params {
+				return New().Docs("doc").InsertRows(&InsertRowsRequest{Index: 1, Count: 2, Values: [][]interface{}{{"x", "🍣"}}})
+			},
+			want: [][]string{{"a1", "b1"}, {"x", "🍣"}, {"", ""}, {"a2", "日本🍣"}},
+		},
+		{
+			name: "rows below the last row",
+			p: func() *Params {
+				return New().Docs("doc").InsertRows(&InsertRowsRequest{Index: 1, InsertBelow: true, Values: [][]interface{}{{"x"}, {"", "y"}}})
+			},
+			want: [][]string{{"a1", "b1"}, {"a2", "日本🍣"}, {"x", ""}, {"", "y"}},
+		},
+		{
+			name: "columns left of the first column",
+			p: func() *Params {
+				return New().Docs("doc").InsertColumns(&InsertColumnsRequest{Index: 0, Values: [][]interface{}{{"x", "y"}, {"🍣"}}})
+			},
+			want: [][]string{{"x", "y", "a1", "b1"}, {"🍣", "", "a2", "日本🍣"}},
+		},
+		{
+			name: "columns right of the last column",
+			p: func() *Params {
+				return New().Docs("doc").InsertColumns(&InsertColumnsRequest{Index: 1, InsertRight: true, Count: 2, Values: [][]interface{}{nil, {"", "z"}}})
+			},
+			want: [][]string{{"a1", "b1", "", ""}, {"a2", "日本🍣", "", "z"}},
+		},
+		{
+			name: "columns right of the middle column",
+			p: func() *Params {
+				return New().Docs("doc").InsertColumns(&InsertColumnsRequest{Index: 0, InsertRight: true, Values: [][]interface{}{{"x"}, {"y"}}})
+			},
+			want: [][]string{{"a1", "x", "b1"}, {"a2", "y", "日本🍣"}},
+		},
+		{
+			name: "rows without values",
+			p: func() *Params {
+				return New().Docs("doc").InsertRows(&InsertRowsRequest{Index: 0, Count: 1})
+			},
+			want: [][]string{{"", ""}, {"a1", "b1"}, {"a2", "日本🍣"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := assertDryRun(t, newTable, tt.p)
+			assertValues(t, testValues(t, f), tt.want)
+		})
+	}
+}
+
+func TestGetValuesWithTextRuns(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{
+		StyledValue{Value: "Total", Style: &TextStyle{Bold: Bool(true)}},
+		"line1\nline2",
+		"ab",
+	}})
+	cell := func(j int) *docs.TableCell {
+		return testTable(t, f).TableRows[0].TableCells[j]
+	}
+	// The text of 1st cell is split into the bold run and the normal run.
+	start := cell(0).Content[0].StartIndex
+	if err := fakeApply(f, insertText(start+5, ": 5")); err != nil {
+		t.Fatal(err)
+	}
+	// The 2nd paragraph of 2nd cell is split into the bold run and the normal run.
+	start = cell(1).Content[1].StartIndex
+	if err := fakeApply(f, createUpdateTextStyleRequest(start, start+2, &docs.TextStyle{Bold: true}, "bold")); err != nil {
+		t.Fatal(err)
+	}
+	// The image is put between the texts of 3rd cell.
+	start = cell(2).Content[0].StartIndex
+	image := &docs.Request{InsertInlineImage: &docs.InsertInlineImageRequest{Location: &docs.Location{Index: start + 1}, Uri: "https://example.com/image.png"}}
+	if err := fakeApply(f, image); err != nil {
+		t.Fatal(err)
+	}
+	for j, want := range []int{2, 3, 3} {
+		var runs int
+		for _, e := range cell(j).Content {
+			runs += len(e.Paragraph.Elements)
+		}
+		if runs != want {
+			t.Fatalf("cell (0, %d) has %d elements, want %d", j, runs, want)
+		}
+	}
+	assertValues(t, testValues(t, f), [][]string{{"Total: 5", "line1\nline2", "a[INLINE OBJECT]b"}})
+	res, err := New().Docs("doc").SetBackend(f).GetTables().Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, res.Tables[0].Values, [][]string{{"Total: 5", "line1\nline2", "a[INLINE OBJECT]b"}})
+}
+
+func TestDeleteRowsAndColumns(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b", "c"}, {"d", "e", "f"}, {"g", "h", "i"}})
+	b := &hookBackend{FakeBackend: f}
+	d := &DeleteRowsColumnsRequest{Rows: []int64{0, 2, 0}, Columns: []int64{1, 2, 1}}
+	if _, err := New().Docs("doc").SetBackend(b).DeleteRowsAndColumns(d).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, testValues(t, f), [][]string{{"d"}})
+	if got, want := *d, (DeleteRowsColumnsRequest{Rows: []int64{0, 2, 0}, Columns: []int64{1, 2, 1}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("request was modified to %+v", got)
+	}
+	if got := len(b.requests[0]); got != 4 {
+		t.Errorf("%d requests were sent, want 4", got)
+	}
+}
+
+func TestDeleteRowsAndColumnsOutsideOfTable(t *testing.T) {
+	for _, d := range []*DeleteRowsColumnsRequest{
+		{},
+		{Rows: []int64{-1}},
+		{Rows: []int64{2}},
+		{Columns: []int64{0, -1}},
+		{Rows: []int64{0}, Columns: []int64{2}},
+	} {
+		f := newTestTable(t, [][]interface{}{{"a", "b"}, {"c", "d"}})
+		b := &hookBackend{FakeBackend: f}
+		if _, err := New().Docs("doc").SetBackend(b).DeleteRowsAndColumns(d).Do(nil); err == nil {
+			t.Errorf("no error for %+v", d)
+		}
+		if b.batches != 0 {
+			t.Errorf("batchUpdate was requested for %+v", d)
+		}
+	}
+}
+
+func TestExpandTable(t *testing.T) {
+	tableElement := func(f *FakeBackend) *docs.StructuralElement {
+		for _, e := range f.Document("doc").Body.Content {
+			if e.Table != nil {
+				return e
+			}
+		}
+		t.Fatal("table was not found")
+		return nil
+	}
+	tests := []struct {
+		name                           string
+		rowAt, addRows, colAt, addCols int64
+		requests                       func() []*docs.Request
+	}{
+		{
+			name: "rows and columns at the last", rowAt: 2, addRows: 1, colAt: 2, addCols: 2,
+			requests: func() []*docs.Request {
+				return []*docs.Request{
+					{InsertTableRow: &docs.InsertTableRowRequest{TableCellLocation: cellLocation(1, 0), InsertBelow: true}},
+					{InsertTableColumn: &docs.InsertTableColumnRequest{TableCellLocation: cellLocation(0, 1), InsertRight: true}},
+					{InsertTableColumn: &docs.InsertTableColumnRequest{TableCellLocation: cellLocation(0, 2), InsertRight: true}},
+				}
+			},
+		},
+		{
+			name: "rows and columns at the first", rowAt: 0, addRows: 2, colAt: 0, addCols: 1,
+			requests: func() []*docs.Request {
+				return []*docs.Request{
+					{InsertTableRow: &docs.InsertTableRowRequest{TableCellLocation: cellLocation(0, 0)}},
+					{InsertTableRow: &docs.InsertTableRowRequest{TableCellLocation: cellLocation(0, 0)}},
+					{InsertTableColumn: &docs.InsertTableColumnRequest{TableCellLocation: cellLocation(0, 0)}},
+				}
+			},
+		},
+		{
+			name: "rows and columns in the middle", rowAt: 1, addRows: 1, colAt: 1, addCols: 1,
+			requests: func() []*docs.Request {
+				return []*docs.Request{
+					{InsertTableRow: &docs.InsertTableRowRequest{TableCellLocation: cellLocation(1, 0)}},
+					{InsertTableColumn: &docs.InsertTableColumnRequest{TableCellLocation: cellLocation(0, 1)}},
+				}
+			},
+		},
+	}
+	// The table of newTestTable starts at 2 like cellLocation.
+	for _, c := range tests {
+		t.Run(c.name, func(t *testing.T) {
+			f := newTestTable(t, [][]interface{}{{"a1", "日本🍣"}, {"", "b2\nc2"}})
+			e := tableElement(f)
+			got := expandTableAt(e, c.rowAt, c.addRows, c.colAt, c.addCols)
+			if err := fakeApply(f, c.requests()...); err != nil {
+				t.Fatal(err)
+			}
+			want := tableElement(f)
+			if g, w := fakeLayout([]*docs.StructuralElement{got}), fakeLayout([]*docs.StructuralElement{want}); g != w {
+				t.Errorf("expanded table = %s, want %s", g, w)
+			}
+		})
+	}
+	// expandTable adds the rows and columns after the last row and column.
+	f := newTestTable(t, [][]interface{}{{"a1", "日本🍣"}, {"", "b2\nc2"}})
+	e := tableElement(f)
+	if g, w := fakeLayout([]*docs.StructuralElement{expandTable(e, 3, 4)}), fakeLayout([]*docs.StructuralElement{expandTableAt(e, 2, 1, 2, 2)}); g != w {
+		t.Errorf("expandTable = %s, want %s", g, w)
+	}
+}
+
+func TestMergeCells(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "", "c"}, {"", "", "f"}, {"g", "h", "i"}})
+	b := &hookBackend{FakeBackend: f}
+	m := &MergeCellsRequest{Ranges: []CellRange{
+		{Rows: 2, Columns: 2},
+		{StartRowIndex: 2, StartColumnIndex: 1, Rows: 1},
+	}}
+	if _, err := New().Docs("doc").SetBackend(b).MergeCells(m).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []*docs.Request{
+		{MergeTableCells: &docs.MergeTableCellsRequest{TableRange: &docs.TableRange{TableCellLocation: cellLocation(0, 0), RowSpan: 2, ColumnSpan: 2}}},
+		{MergeTableCells: &docs.MergeTableCellsRequest{TableRange: &docs.TableRange{TableCellLocation: cellLocation(2, 1), RowSpan: 1, ColumnSpan: 2}}},
+	}
+	if len(b.requests) != 1 || !reflect.DeepEqual(b.requests[0], want) {
+		t.Fatalf("requests = %s, want %s", requestsJSON(b.requests), requestsJSON([][]*docs.Request{want}))
+	}
+	merged := []MergedCell{{Row: 0, Column: 0, RowSpan: 2, ColumnSpan: 2}, {Row: 2, Column: 1, RowSpan: 1, ColumnSpan: 2}}
+	res, err := New().Docs("doc").SetBackend(f).GetValues().Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res.MergedCells, merged) {
+		t.Errorf("merged cells of GetValues = %+v, want %+v", res.MergedCells, merged)
+	}
+	res, err = New().Docs("doc").SetBackend(f).GetTables().Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res.Tables[0].MergedCells, merged) {
+		t.Errorf("merged cells of GetTables = %+v, want %+v", res.Tables[0].MergedCells, merged)
+	}
+
+	// The value cannot be put to the cell merged into other cell, while the empty value can be put to it.
+	b = &hookBackend{FakeBackend: f}
+	if _, err := New().Docs("doc").SetBackend(b).SetValuesBy2DArray([][]interface{}{{"x", ""}, {"", "y"}}).Do(nil); err == nil || err.Error() != "Cell (1, 1) is merged into cell (0, 0)" {
+		t.Errorf("err = %v, want the error of the merged cell", err)
+	}
+	if _, err := New().Docs("doc").SetBackend(b).SetValuesBy2DArray([][]interface{}{{"x", ""}}).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New().Docs("doc").SetBackend(b).MoveRow(0, 2).Do(nil); err == nil {
+		t.Error("no error for moving the row of the merged cells")
+	}
+
+	if _, err := New().Docs("doc").SetBackend(f).UnmergeCells(&MergeCellsRequest{Ranges: []CellRange{{Rows: 2, Columns: 2}}}).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	res, err = New().Docs("doc").SetBackend(f).GetValues().Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res.MergedCells, merged[1:]) {
+		t.Errorf("merged cells after UnmergeCells = %+v, want %+v", res.MergedCells, merged[1:])
+	}
+	if res.Values[0][0] != "x" {
+		t.Errorf("value of the head cell = %q, want %q", res.Values[0][0], "x")
+	}
+}
+
+func TestMergeCellsInvalidRanges(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}, {"c", "d"}})
+	b := &hookBackend{FakeBackend: f}
+	for _, p := range []*Params{
+		New().MergeCells(nil),
+		New().MergeCells(&MergeCellsRequest{}),
+		New().MergeCells(&MergeCellsRequest{Ranges: []CellRange{{Rows: 1, Columns: 1}}}),
+		New().MergeCells(&MergeCellsRequest{Ranges: []CellRange{{StartColumnIndex: 2}}}),
+		New().MergeCells(&MergeCellsRequest{Ranges: []CellRange{{Rows: 2}, {StartRowIndex: 1, Rows: 2}}}),
+		New().UnmergeCells(&MergeCellsRequest{Ranges: []CellRange{{StartColumnIndex: -1}}}),
+	} {
+		if _, err := p.Docs("doc").SetBackend(b).Do(nil); err == nil {
+			t.Errorf("no error for %+v", p.MergeCellsRequest)
+		}
+	}
+	if b.batches != 0 {
+		t.Errorf("batchUpdate was requested %d times, want 0", b.batches)
+	}
+}
+
+func TestDeleteRowsWhere(t *testing.T) {
+	values := [][]interface{}{{"name", "status"}, {"a", "done"}, {"b", "todo"}, {"c", "done"}}
+	f := newTestTable(t, values)
+	b := &hookBackend{FakeBackend: f}
+	res, err := New().Docs("doc").SetBackend(b).DeleteRowsByValue(1, "done").Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, testValues(t, f), [][]string{{"name", "status"}, {"b", "todo"}})
+	if want := []int64{1, 3}; !reflect.DeepEqual(res.DeletedRows, want) {
+		t.Errorf("deleted rows = %v, want %v", res.DeletedRows, want)
+	}
+	// The rows are deleted from the last row by one batchUpdate.
+	var rows []int64
+	for _, r := range b.requests[0] {
+		rows = append(rows, r.DeleteTableRow.TableCellLocation.RowIndex)
+	}
+	if want := []int64{3, 1}; len(b.requests) != 1 || !reflect.DeepEqual(rows, want) {
+		t.Errorf("requests = %s, want DeleteTableRow of rows %v", requestsJSON(b.requests), want)
+	}
+
+	f = newTestTable(t, values)
+	res, err = New().Docs("doc").SetBackend(f).DeleteRowsWhere(func(row []string) bool { return row[0] == "b" || row[1] == "done" }).Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, testValues(t, f), [][]string{{"name", "status"}})
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(res.DeletedRows, want) {
+		t.Errorf("deleted rows = %v, want %v", res.DeletedRows, want)
+	}
+}
+
+func TestDeleteRowsWhereWithoutDeleting(t *testing.T) {
+	values := [][]interface{}{{"a", "b"}, {"c", "d"}}
+	for _, c := range []struct {
+		name string
+		p    *Params
+		err  bool
+	}{
+		{"no matched rows", New().DeleteRowsByValue(0, "x"), false},
+		{"column outside of the table", New().DeleteRowsByValue(2, ""), false},
+		{"all rows", New().DeleteRowsWhere(func(row []string) bool { return true }), true},
+		{"no function", New().DeleteRowsWhere(nil), true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			f := newTestTable(t, values)
+			b := &hookBackend{FakeBackend: f}
+			res, err := c.p.Docs("doc").SetBackend(b).Do(nil)
+			if (err != nil) != c.err {
+				t.Fatalf("err = %v, want error: %v", err, c.err)
+			}
+			if err == nil && len(res.DeletedRows) != 0 {
+				t.Errorf("deleted rows = %v, want none", res.DeletedRows)
+			}
+			if b.batches != 0 {
+				t.Errorf("batchUpdate was requested %d times, want 0", b.batches)
+			}
+			assertValues(t, testValues(t, f), [][]string{{"a", "b"}, {"c", "d"}})
+		})
+	}
+}
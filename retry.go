@@ -0,0 +1,92 @@
+// Package gdoctableapp (retry.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the retry of requests.
+package gdoctableapp
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// DefaultRetryPolicy : Create the retry policy for the quota errors and the server errors of APIs.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     32 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		StatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// retryable : Check whether the request with the status code is retried.
+func (r *RetryPolicy) retryable(code int) bool {
+	for _, e := range r.StatusCodes {
+		if e == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff : Calculate the waiting time before the next attempt.
+func (r *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := r.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	wait := float64(r.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if r.MaxBackoff > 0 && wait > float64(r.MaxBackoff) {
+		wait = float64(r.MaxBackoff)
+	}
+	if r.Jitter > 0 {
+		wait += wait * r.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(wait)
+}
+
+// callOnce : Run the request of op without the retry.
+func (o *obj) callOnce(op string, f func() error) error {
+	return o.wrapError(op, f())
+}
+
+// call : Run the request of op. When the request fails with the status code of RetryPolicy, the request is retried.
+// The retried attempts are recorded in Result.RetryAttempts.
+func (o *obj) call(op string, f func() error) error {
+	p := o.params.RetryPolicy
+	for attempt := 1; ; attempt++ {
+		err := o.wrapError(op, f())
+		if err == nil {
+			return nil
+		}
+		var apiErr *APIError
+		if p == nil || attempt >= p.MaxAttempts || !errors.As(err, &apiErr) || !p.retryable(apiErr.Code) {
+			return err
+		}
+		wait := p.backoff(attempt)
+		o.result.RetryAttempts = append(o.result.RetryAttempts, RetryAttempt{
+			Op:         op,
+			Attempt:    attempt,
+			StatusCode: apiErr.Code,
+			Error:      apiErr.Err.Error(),
+			Wait:       wait,
+		})
+		timer := time.NewTimer(wait)
+		select {
+		case <-o.ctx.Done():
+			timer.Stop()
+			return &CanceledError{Op: op, Err: o.ctx.Err()}
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package gdoctableapp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	drive "google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// applyThenFail : Return the server error after the 1st batchUpdate was applied.
+func applyThenFail(n int) error {
+	if n == 1 {
+		return &googleapi.Error{Code: http.StatusServiceUnavailable, Message: "backend error"}
+	}
+	return nil
+}
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: 3, StatusCodes: []int{http.StatusServiceUnavailable}}
+}
+
+func TestRetryBatchUpdateWithoutRevision(t *testing.T) {
+	f := NewFakeBackend()
+	f.NewDocument("doc")
+	b := &hookBackend{FakeBackend: f, after: applyThenFail}
+	c := &CreateTableRequest{Rows: 1, Columns: 1, Index: 1}
+	_, err := New().Docs("doc").SetBackend(b).CreateTable(c).Retry(testRetryPolicy()).Do(nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want APIError of 503", err)
+	}
+	if b.batches != 1 {
+		t.Errorf("batchUpdate was requested %d times, want 1", b.batches)
+	}
+	var tables int
+	for _, e := range f.Document("doc").Body.Content {
+		if e.Table != nil {
+			tables++
+		}
+	}
+	if tables != 1 {
+		t.Errorf("%d tables were created, want 1", tables)
+	}
+}
+
+func TestRetryBatchUpdateWithRevisionAppliedBeforeError(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}})
+	b := &hookBackend{FakeBackend: f, after: applyThenFail}
+	res, err := New().Docs("doc").SetBackend(b).SetValuesBy2DArray([][]interface{}{{"c"}}).Retry(testRetryPolicy()).RetryOnConflict(3).Do(nil)
+	var conflict *ConflictError
+	if !errors.Is(err, errMaybeApplied) || errors.As(err, &conflict) {
+		t.Fatalf("err = %v, %v, want the error of the applied request", res, err)
+	}
+	if b.batches != 1 {
+		t.Errorf("batchUpdate was requested %d times, want 1", b.batches)
+	}
+	assertValues(t, testValues(t, f), [][]string{{"c", "b"}})
+}
+
+func TestRetryBatchUpdateWithRevisionNotApplied(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}})
+	b := &hookBackend{FakeBackend: f, before: applyThenFail}
+	res, err := New().Docs("doc").SetBackend(b).SetValuesBy2DArray([][]interface{}{{"c"}}).Retry(testRetryPolicy()).Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.batches != 2 || len(res.RetryAttempts) != 1 {
+		t.Errorf("batchUpdate was requested %d times with %d retries, want 2 and 1", b.batches, len(res.RetryAttempts))
+	}
+	assertValues(t, testValues(t, f), [][]string{{"c", "b"}})
+}
+
+// createThenFail : FakeBackend which returns the server error after the file was created.
+type createThenFail struct {
+	*FakeBackend
+	creates int
+}
+
+func (b *createThenFail) CreateFile(ctx context.Context, file *drive.File, media io.Reader) (*drive.File, error) {
+	b.creates++
+	if _, err := b.FakeBackend.CreateFile(ctx, file, media); err != nil {
+		return nil, err
+	}
+	return nil, &googleapi.Error{Code: http.StatusServiceUnavailable, Message: "backend error"}
+}
+
+func TestRetryCreateFile(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"IMG", "b"}})
+	b := &createThenFail{FakeBackend: f}
+	_, err := New().Docs("doc").SetBackend(b).ReplaceTextsToImagesByFile("IMG", "helpers_test.go").Retry(testRetryPolicy()).Do(nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want APIError of 503", err)
+	}
+	if b.creates != 1 {
+		t.Errorf("file was created %d times, want 1", b.creates)
+	}
+}
@@ -0,0 +1,208 @@
+// Package gdoctableapp (selector.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the selection of the table by the content.
+package gdoctableapp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+const (
+	selectByHeader     = "header"
+	selectByFirstCell  = "firstCell"
+	selectByHeading    = "heading"
+	selectByHeadingID  = "headingID"
+	selectByNamedRange = "namedRange"
+
+	// headingFields : Fields for retrieving the tables and the headings.
+	headingFields = "body(content(endIndex,startIndex,table,paragraph(elements(startIndex,endIndex,textRun(content)),paragraphStyle(namedStyleType,headingId))))"
+)
+
+// selectTable : Select the table from contents by TableSelectorP.
+// When zero or multiple tables match, an error is returned.
+func (o *obj) selectTable(contents []*docs.StructuralElement) error {
+	switch o.params.TableSelectorP.By {
+	case selectByHeading, selectByHeadingID:
+		return o.selectTableUnderHeading(contents)
+	case selectByNamedRange:
+		return o.selectTableInNamedRange(contents)
+	}
+	var matched []int
+	var matchedTable *docs.StructuralElement
+	c := 0
+	for _, e := range contents {
+		if e.Table == nil {
+			continue
+		}
+		o.docTable = e
+		values, err := o.getValues()
+		if err != nil {
+			return err
+		}
+		if o.matchTable(values) {
+			matched = append(matched, c)
+			matchedTable = e
+		}
+		c++
+	}
+	o.docTable = nil
+	return o.setSelectedTable(matched, matchedTable)
+}
+
+// setSelectedTable : Set the selected table. When zero or multiple tables match, an error is returned.
+func (o *obj) setSelectedTable(matched []int, table *docs.StructuralElement) error {
+	switch len(matched) {
+	case 0:
+		return fmt.Errorf("Table of %s was not found", o.selectorDescription())
+	case 1:
+		o.docTable = table
+		o.params.TableIdx = matched[0]
+		// The table is retrieved by the index after this, because the values of the table might be changed by the method.
+		o.params.TableSelectorP.By = ""
+		return nil
+	}
+	return fmt.Errorf("%d tables of %s were found. Table indexes are %v", len(matched), o.selectorDescription(), matched)
+}
+
+// selectTableUnderHeading : Select the first table in the section of the heading.
+// The section is from the heading to the next heading of the same or higher level.
+func (o *obj) selectTableUnderHeading(contents []*docs.StructuralElement) error {
+	var matched []int
+	var matchedTable *docs.StructuralElement
+	inSection := false
+	sectionLevel := 0
+	c := 0
+	for _, e := range contents {
+		if e.Paragraph != nil {
+			level := headingLevel(e.Paragraph)
+			if level == 0 {
+				continue
+			}
+			if inSection && level <= sectionLevel {
+				inSection = false
+			}
+			if !inSection && o.matchHeading(e.Paragraph, level) {
+				inSection = true
+				sectionLevel = level
+			}
+			continue
+		}
+		if e.Table == nil {
+			continue
+		}
+		if inSection {
+			matched = append(matched, c)
+			matchedTable = e
+			inSection = false
+		}
+		c++
+	}
+	return o.setSelectedTable(matched, matchedTable)
+}
+
+// matchHeading : Check whether the heading paragraph matches TableSelectorP.
+func (o *obj) matchHeading(p *docs.Paragraph, level int) bool {
+	s := o.params.TableSelectorP
+	if s.By == selectByHeadingID {
+		return p.ParagraphStyle.HeadingId == s.HeadingID
+	}
+	if s.HeadingLevel != 0 && s.HeadingLevel != level {
+		return false
+	}
+	return strings.TrimSpace(paragraphText(p)) == strings.TrimSpace(s.Heading)
+}
+
+// selectTableInNamedRange : Select the first table which includes or follows the start of the named range.
+func (o *obj) selectTableInNamedRange(contents []*docs.StructuralElement) error {
+	s := o.params.TableSelectorP
+	ranges, ok := o.namedRanges[s.NamedRange]
+	if !ok || len(ranges.NamedRanges) == 0 || len(ranges.NamedRanges[0].Ranges) == 0 {
+		return fmt.Errorf("Named range of %q was not found", s.NamedRange)
+	}
+	if len(ranges.NamedRanges) > 1 {
+		return fmt.Errorf("%d named ranges of %q were found", len(ranges.NamedRanges), s.NamedRange)
+	}
+	start := ranges.NamedRanges[0].Ranges[0].StartIndex
+	c := 0
+	for _, e := range contents {
+		if e.Table == nil {
+			continue
+		}
+		if e.EndIndex > start {
+			return o.setSelectedTable([]int{c}, e)
+		}
+		c++
+	}
+	return o.setSelectedTable(nil, nil)
+}
+
+// headingLevel : Return the level of the heading. When the paragraph is not a heading, 0 is returned.
+func headingLevel(p *docs.Paragraph) int {
+	if p.ParagraphStyle == nil || !strings.HasPrefix(p.ParagraphStyle.NamedStyleType, "HEADING_") {
+		return 0
+	}
+	level, err := strconv.Atoi(strings.TrimPrefix(p.ParagraphStyle.NamedStyleType, "HEADING_"))
+	if err != nil {
+		return 0
+	}
+	return level
+}
+
+// paragraphText : Return the text of the paragraph.
+func paragraphText(p *docs.Paragraph) string {
+	var texts []string
+	for _, e := range p.Elements {
+		if e.TextRun != nil {
+			texts = append(texts, e.TextRun.Content)
+		}
+	}
+	return strings.Join(texts, "")
+}
+
+// matchTable : Check whether the values of the table match TableSelectorP.
+func (o *obj) matchTable(values [][]string) bool {
+	s := o.params.TableSelectorP
+	if len(values) == 0 {
+		return false
+	}
+	switch s.By {
+	case selectByHeader:
+		if len(values[0]) < len(s.Header) {
+			return false
+		}
+		for i, e := range s.Header {
+			if strings.TrimSpace(values[0][i]) != strings.TrimSpace(e) {
+				return false
+			}
+		}
+		return true
+	case selectByFirstCell:
+		return len(values[0]) > 0 && strings.TrimSpace(values[0][0]) == strings.TrimSpace(s.FirstCell)
+	}
+	return false
+}
+
+// selectorDescription : Description of TableSelectorP for the error messages.
+func (o *obj) selectorDescription() string {
+	s := o.params.TableSelectorP
+	switch s.By {
+	case selectByHeader:
+		return fmt.Sprintf("the header row of %q", s.Header)
+	case selectByFirstCell:
+		return fmt.Sprintf("the first cell of %q", s.FirstCell)
+	case selectByHeading:
+		if s.HeadingLevel != 0 {
+			return fmt.Sprintf("the heading %d of %q", s.HeadingLevel, s.Heading)
+		}
+		return fmt.Sprintf("the heading of %q", s.Heading)
+	case selectByHeadingID:
+		return fmt.Sprintf("the heading ID of %q", s.HeadingID)
+	case selectByNamedRange:
+		return fmt.Sprintf("the named range of %q", s.NamedRange)
+	}
+	return fmt.Sprintf("index of %d", o.params.TableIdx)
+}
@@ -0,0 +1,145 @@
+package gdoctableapp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// testSection : Heading and the table following it.
+type testSection struct {
+	heading string
+	level   int
+	values  [][]interface{}
+}
+
+// newSectionsDocument : Create the Document of "doc" including the sections in order.
+// The heading IDs are "h.1", "h.2", ... in order of the sections.
+func newSectionsDocument(t *testing.T, sections []testSection) *FakeBackend {
+	t.Helper()
+	f := NewFakeBackend()
+	f.NewDocument("doc")
+	for _, s := range sections {
+		content := f.Document("doc").Body.Content
+		if err := fakeApply(f, insertText(content[len(content)-1].EndIndex-1, s.heading+"\n")); err != nil {
+			t.Fatal(err)
+		}
+		c := &CreateTableRequest{Rows: int64(len(s.values)), Columns: int64(len(s.values[0])), Append: true, Values: s.values}
+		if _, err := New().Docs("doc").SetBackend(f).CreateTable(c).Do(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := f.Document("doc")
+	n := 0
+	for _, e := range d.Body.Content {
+		if e.Paragraph == nil || n >= len(sections) || paragraphText(e.Paragraph) != sections[n].heading+"\n" {
+			continue
+		}
+		e.Paragraph.ParagraphStyle = &docs.ParagraphStyle{
+			NamedStyleType: fmt.Sprintf("HEADING_%d", sections[n].level),
+			HeadingId:      fmt.Sprintf("h.%d", n+1),
+		}
+		n++
+	}
+	if n != len(sections) {
+		t.Fatalf("%d headings were found, want %d", n, len(sections))
+	}
+	f.SetDocument("doc", d)
+	return f
+}
+
+// testSections : Sections for the tests of the selectors. The 1st and 3rd tables have the same header.
+var testSections = []testSection{
+	{"Title", 1, [][]interface{}{{"id", "name"}, {"1", "a"}}},
+	{"Section", 2, [][]interface{}{{"key", "value"}, {"2", "b"}}},
+	{"Other", 1, [][]interface{}{{"id", "name"}, {"3", "c"}}},
+}
+
+// assertSelected : Check that the table selected by p is the table of the section of want.
+// When want is -1, an error including errText is expected.
+func assertSelected(t *testing.T, f *FakeBackend, p *Params, want int, errText string) {
+	t.Helper()
+	res, err := p.Docs("doc").SetBackend(f).GetValues().Do(nil)
+	if want < 0 {
+		if err == nil || !strings.Contains(err.Error(), errText) {
+			t.Errorf("err = %v, want the error including %q", err, errText)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Values[1][0]; got != fmt.Sprint(want+1) {
+		t.Errorf("table of section %s was selected, want %d", got, want+1)
+	}
+}
+
+func TestTableByHeaderAndFirstCell(t *testing.T) {
+	f := newSectionsDocument(t, testSections)
+	assertSelected(t, f, New().TableByHeader([]string{"key", "value"}), 1, "")
+	assertSelected(t, f, New().TableByHeader([]string{" key "}), 1, "")
+	assertSelected(t, f, New().TableByHeader([]string{"id", "name"}), -1, "2 tables of the header row")
+	assertSelected(t, f, New().TableByHeader([]string{"key", "value", "x"}), -1, "was not found")
+	assertSelected(t, f, New().TableByFirstCell("key"), 1, "")
+	assertSelected(t, f, New().TableByFirstCell("id"), -1, "Table indexes are [0 2]")
+	assertSelected(t, f, New().TableByFirstCell("value"), -1, "was not found")
+}
+
+func TestTableUnderHeading(t *testing.T) {
+	f := newSectionsDocument(t, testSections)
+	assertSelected(t, f, New().TableUnderHeading("Title", 1), 0, "")
+	assertSelected(t, f, New().TableUnderHeading("Section", 0), 1, "")
+	assertSelected(t, f, New().TableUnderHeading("Other", 0), 2, "")
+	assertSelected(t, f, New().TableUnderHeading("Title", 2), -1, "was not found")
+	assertSelected(t, f, New().TableUnderHeading("Table", 0), -1, "was not found")
+	assertSelected(t, f, New().TableUnderHeadingID("h.2"), 1, "")
+	assertSelected(t, f, New().TableUnderHeadingID("h.3"), 2, "")
+	assertSelected(t, f, New().TableUnderHeadingID("h.4"), -1, "was not found")
+}
+
+func TestTableUnderHeadingWithoutTable(t *testing.T) {
+	f := newSectionsDocument(t, testSections[:1])
+	content := f.Document("doc").Body.Content
+	if err := fakeApply(f, insertText(content[len(content)-1].EndIndex-1, "Last\n")); err != nil {
+		t.Fatal(err)
+	}
+	d := f.Document("doc")
+	d.Body.Content[len(d.Body.Content)-2].Paragraph.ParagraphStyle = &docs.ParagraphStyle{NamedStyleType: "HEADING_1", HeadingId: "h.2"}
+	f.SetDocument("doc", d)
+	// The table of the previous section is not used for the heading without the table.
+	assertSelected(t, f, New().TableUnderHeading("Last", 1), -1, "was not found")
+}
+
+func TestTableInNamedRange(t *testing.T) {
+	f := newSectionsDocument(t, testSections)
+	d := f.Document("doc")
+	var tables, headings []*docs.StructuralElement
+	for _, e := range d.Body.Content {
+		switch {
+		case e.Table != nil:
+			tables = append(tables, e)
+		case e.Paragraph != nil && headingLevel(e.Paragraph) > 0:
+			headings = append(headings, e)
+		}
+	}
+	namedRange := func(name string, start, end int64) docs.NamedRanges {
+		return docs.NamedRanges{Name: name, NamedRanges: []*docs.NamedRange{{Name: name, Ranges: []*docs.Range{{StartIndex: start, EndIndex: end}}}}}
+	}
+	// "cell" starts in the 3rd table, and "section" starts at the heading of the 2nd section.
+	d.NamedRanges = map[string]docs.NamedRanges{
+		"cell":    namedRange("cell", tables[2].StartIndex+5, tables[2].StartIndex+6),
+		"section": namedRange("section", headings[1].StartIndex, tables[1].EndIndex),
+		"last":    namedRange("last", tables[2].EndIndex, tables[2].EndIndex+1),
+	}
+	dup := namedRange("dup", 1, 2)
+	dup.NamedRanges = append(dup.NamedRanges, dup.NamedRanges[0])
+	d.NamedRanges["dup"] = dup
+	f.SetDocument("doc", d)
+	assertSelected(t, f, New().TableInNamedRange("cell"), 2, "")
+	assertSelected(t, f, New().TableInNamedRange("section"), 1, "")
+	assertSelected(t, f, New().TableInNamedRange("last"), -1, "was not found")
+	assertSelected(t, f, New().TableInNamedRange("unknown"), -1, `Named range of "unknown" was not found`)
+	assertSelected(t, f, New().TableInNamedRange("dup"), -1, `2 named ranges of "dup"`)
+}
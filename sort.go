@@ -0,0 +1,192 @@
+// Package gdoctableapp (sort.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the methods for sorting rows.
+package gdoctableapp
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// Types of SortKey.
+const (
+	SortTypeString = "string"
+	SortTypeNumber = "number"
+	SortTypeDate   = "date"
+)
+
+// sortValue : Parsed value of a cell for sorting.
+type sortValue struct {
+	ok     bool // When false, the value is empty or cannot be parsed. Such values are put to the last.
+	text   string
+	number float64
+	date   time.Time
+}
+
+// sortRows : Sort the data rows of the table, and rewrite the cells whose values are changed.
+func (o *obj) sortRows() error {
+	s := o.params.SortRowsRequest
+	if s == nil || len(s.Keys) == 0 {
+		return fmt.Errorf("No keys for using SortRows()")
+	}
+	table := o.docTable.Table
+	if s.HeaderRows < 0 || s.HeaderRows > table.Rows {
+		return fmt.Errorf("HeaderRows must be from 0 to the rows of the table")
+	}
+	for _, k := range s.Keys {
+		if k.Column < 0 || k.Column >= table.Columns {
+			return fmt.Errorf("Column %d of the sort key is outside of the table", k.Column)
+		}
+		switch k.Type {
+		case "", SortTypeString, SortTypeNumber, SortTypeDate:
+		default:
+			return fmt.Errorf("Invalid type of the sort key: %q", k.Type)
+		}
+	}
+	o.parseTable()
+	values, err := o.getValues()
+	if err != nil {
+		return err
+	}
+	keys := make([][]sortValue, len(values))
+	order := []int64{}
+	for i := s.HeaderRows; i < int64(len(values)); i++ {
+		for _, k := range s.Keys {
+			keys[i] = append(keys[i], parseSortValue(values[i][k.Column], k))
+		}
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ka, kb := keys[order[a]], keys[order[b]]
+		for n, k := range s.Keys {
+			c := compareSortValues(ka[n], kb[n], k)
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	sources := map[int64]int64{}
+	for i, src := range order {
+		sources[s.HeaderRows+int64(i)] = src
+	}
+	return o.rewriteRows(sources, values)
+}
+
+// parseSortValue : Parse the value of the cell for the type of the sort key.
+func parseSortValue(v string, k SortKey) sortValue {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return sortValue{}
+	}
+	switch k.Type {
+	case SortTypeNumber:
+		f, err := strconv.ParseFloat(removeThousandsSeparator(v), 64)
+		if err != nil {
+			return sortValue{}
+		}
+		return sortValue{ok: true, number: f}
+	case SortTypeDate:
+		t, err := parseTime(v, k.Layout)
+		if err != nil {
+			return sortValue{}
+		}
+		return sortValue{ok: true, date: t}
+	}
+	if k.CaseInsensitive {
+		v = strings.ToLower(v)
+	}
+	return sortValue{ok: true, text: v}
+}
+
+// compareSortValues : Compare a and b by the sort key. The values which are not ok are always put to the last.
+func compareSortValues(a, b sortValue, k SortKey) int {
+	if !a.ok || !b.ok {
+		switch {
+		case a.ok:
+			return -1
+		case b.ok:
+			return 1
+		}
+		return 0
+	}
+	var c int
+	switch k.Type {
+	case SortTypeNumber:
+		switch {
+		case a.number < b.number:
+			c = -1
+		case a.number > b.number:
+			c = 1
+		}
+	case SortTypeDate:
+		switch {
+		case a.date.Before(b.date):
+			c = -1
+		case a.date.After(b.date):
+			c = 1
+		}
+	default:
+		c = strings.Compare(a.text, b.text)
+	}
+	if k.Descending {
+		c = -c
+	}
+	return c
+}
+
+// rewriteRows : Rewrite the rows of the table. sources is the map of the row index to the row index of the source row.
+// Only the cells whose texts or styles of texts are different from those of the source cells are rewritten. The styles of texts
+// are copied. The styles of cells like the background colors are not copied, so they are kept at the same positions.
+// All requests are run by one batchUpdate.
+func (o *obj) rewriteRows(sources map[int64]int64, values [][]string) error {
+	if len(o.mergedCells) > 0 {
+		return fmt.Errorf("Rows of the table including the merged cells cannot be rewritten")
+	}
+	rows := o.docTable.Table.TableRows
+	br := &docs.BatchUpdateDocumentRequest{}
+	for i := int64(len(rows)) - 1; i >= 0; i-- {
+		src, ok := sources[i]
+		if !ok || src == i {
+			continue
+		}
+		for j := int64(len(rows[i].TableCells)) - 1; j >= 0; j-- {
+			if j >= int64(len(rows[src].TableCells)) || sameCellTexts(rows[i].TableCells[j], rows[src].TableCells[j], values[i][j], values[src][j]) {
+				continue
+			}
+			requests, err := createCopyCellRequests(rows[src].TableCells[j], o.delCell[i][j].DeleteContentRange.Range.StartIndex)
+			if err != nil {
+				return fmt.Errorf("Cell (%d, %d) cannot be copied: %v", src, j, err)
+			}
+			if r := o.delCell[i][j].DeleteContentRange.Range; r.StartIndex != r.EndIndex {
+				br.Requests = append(br.Requests, o.delCell[i][j])
+			}
+			br.Requests = append(br.Requests, requests...)
+		}
+	}
+	if len(br.Requests) == 0 {
+		return nil
+	}
+	o.requestBody = br
+	if err := o.documentbatchUpdate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// sameCellTexts : Return true when the texts and the styles of texts of the cells a and b are the same.
+// When the cells include the contents except for texts, the values of the cells va and vb are compared.
+func sameCellTexts(a, b *docs.TableCell, va, vb string) bool {
+	ta, ra, errA := cellText(a)
+	tb, rb, errB := cellText(b)
+	if errA != nil || errB != nil {
+		return va == vb
+	}
+	return ta == tb && reflect.DeepEqual(ra, rb)
+}
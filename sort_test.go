@@ -0,0 +1,165 @@
+package gdoctableapp
+
+import (
+	"reflect"
+	"testing"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// touchedCells : Return the cells of the table which are changed by InsertText and DeleteContentRange of requests.
+// The requests are required to be sorted from the end of the Document like rewriteRows.
+func touchedCells(table *docs.Table, requests []*docs.Request) map[[2]int]bool {
+	res := map[[2]int]bool{}
+	for _, r := range requests {
+		var index int64
+		switch {
+		case r.InsertText != nil:
+			index = r.InsertText.Location.Index
+		case r.DeleteContentRange != nil:
+			index = r.DeleteContentRange.Range.StartIndex
+		default:
+			continue
+		}
+		for i, row := range table.TableRows {
+			for j, cell := range row.TableCells {
+				if cell.StartIndex <= index && index < cell.EndIndex {
+					res[[2]int{i, j}] = true
+				}
+			}
+		}
+	}
+	return res
+}
+
+func TestSortRows(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{
+		{"name", "score", "date"},
+		{StyledValue{Value: "a", Style: &TextStyle{Bold: Bool(true)}}, "10", "2023-01-02"},
+		{"b", "2", "2023/01/01"},
+		{"c", "", "2023-01-03"},
+		{"d", "x", "2023-01-01"},
+		{"e", "1,0", "2023-01-01"},
+		{"f", "2", ""},
+	})
+	before := testTable(t, f)
+	initial := cellSignatures(before)
+	b := &hookBackend{FakeBackend: f}
+	s := &SortRowsRequest{
+		HeaderRows: 1,
+		Keys: []SortKey{
+			{Column: 1, Type: SortTypeNumber},
+			{Column: 2, Type: SortTypeDate, Descending: true},
+		},
+	}
+	if _, err := New().Docs("doc").SetBackend(b).SortRows(s).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	// The rows of the same score are sorted by the date in descending order, and the empty date is put to the last.
+	// The empty score and the score which cannot be parsed are put to the last, and they are sorted by the date.
+	assertValues(t, testValues(t, f), [][]string{
+		{"name", "score", "date"},
+		{"b", "2", "2023/01/01"},
+		{"f", "2", ""},
+		{"a", "10", "2023-01-02"},
+		{"e", "1,0", "2023-01-01"},
+		{"c", "", "2023-01-03"},
+		{"d", "x", "2023-01-01"},
+	})
+	var want [][]string
+	for _, i := range []int{0, 2, 6, 1, 5, 3, 4} {
+		want = append(want, initial[i])
+	}
+	if got := cellSignatures(testTable(t, f)); !reflect.DeepEqual(got, want) {
+		t.Errorf("cells = %q, want %q", got, want)
+	}
+	if len(b.requests) != 1 {
+		t.Fatalf("batchUpdate was requested %d times, want 1", len(b.requests))
+	}
+	got := touchedCells(before, b.requests[0])
+	for i := 0; i < len(want); i++ {
+		for j := 0; j < 3; j++ {
+			changed := want[i][j] != initial[i][j]
+			if got[[2]int{i, j}] != changed {
+				t.Errorf("cell (%d, %d) is rewritten: %v, want %v", i, j, got[[2]int{i, j}], changed)
+			}
+		}
+	}
+}
+
+func TestSortRowsWithoutChanges(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"1", "b"}, {"", "a"}, {"x", "c"}})
+	b := &hookBackend{FakeBackend: f}
+	if _, err := New().Docs("doc").SetBackend(b).SortRows(&SortRowsRequest{Keys: []SortKey{{Column: 0, Type: SortTypeNumber}}}).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	if b.batches != 0 {
+		t.Errorf("batchUpdate was requested %d times, want 0", b.batches)
+	}
+	assertValues(t, testValues(t, f), [][]string{{"1", "b"}, {"", "a"}, {"x", "c"}})
+}
+
+func TestSortRowsInvalidKeys(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"1", "b"}, {"2", "a"}})
+	for _, s := range []*SortRowsRequest{
+		{},
+		{Keys: []SortKey{{Column: 2}}},
+		{Keys: []SortKey{{Column: 0, Type: "boolean"}}},
+		{HeaderRows: 3, Keys: []SortKey{{Column: 0}}},
+	} {
+		if _, err := New().Docs("doc").SetBackend(f).SortRows(s).Do(nil); err == nil {
+			t.Errorf("no error for %+v", s)
+		}
+	}
+}
+
+func TestSortRowsByStrings(t *testing.T) {
+	values := [][]interface{}{{"b"}, {"A"}, {"10"}, {"a"}, {"B"}, {"2"}}
+	for _, c := range []struct {
+		name string
+		key  SortKey
+		want [][]string
+	}{
+		// The texts are compared byte by byte, so the numbers are sorted as texts.
+		{"case sensitive", SortKey{}, [][]string{{"10"}, {"2"}, {"A"}, {"B"}, {"a"}, {"b"}}},
+		// The rows with the same texts without the case keep the original order.
+		{"case insensitive", SortKey{CaseInsensitive: true}, [][]string{{"10"}, {"2"}, {"A"}, {"a"}, {"b"}, {"B"}}},
+		{"case insensitive in descending order", SortKey{CaseInsensitive: true, Descending: true}, [][]string{{"b"}, {"B"}, {"A"}, {"a"}, {"2"}, {"10"}}},
+		{"numbers", SortKey{Type: SortTypeNumber}, [][]string{{"2"}, {"10"}, {"b"}, {"A"}, {"a"}, {"B"}}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			f := newTestTable(t, values)
+			if _, err := New().Docs("doc").SetBackend(f).SortRows(&SortRowsRequest{Keys: []SortKey{c.key}}).Do(nil); err != nil {
+				t.Fatal(err)
+			}
+			assertValues(t, testValues(t, f), c.want)
+		})
+	}
+}
+
+func TestSortRowsWithDifferentStyles(t *testing.T) {
+	// The cells of the 1st column have the same text with the different styles, and the cells of the 3rd column are the same.
+	f := newTestTable(t, [][]interface{}{
+		{StyledValue{Value: "x", Style: &TextStyle{Bold: Bool(true)}}, "2", "y"},
+		{"x", "1", "y"},
+		{StyledValue{Value: "x", Style: &TextStyle{Italic: Bool(true)}}, "3", "y"},
+	})
+	if _, err := New().Docs("doc").SetBackend(f).SetCellStyle(HeaderHighlight("#ff0000")).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	before := testTable(t, f)
+	b := &hookBackend{FakeBackend: f}
+	if _, err := New().Docs("doc").SetBackend(b).SortRows(&SortRowsRequest{Keys: []SortKey{{Column: 1, Type: SortTypeNumber}}}).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	// The styles of texts are moved with the rows, and the background color of the 1st row is kept.
+	want := [][]string{{"x[1 0 0]", "1[1 0 0]", "y[1 0 0]"}, {"*x*", "2", "y"}, {"_x_", "3", "y"}}
+	if got := cellSignatures(testTable(t, f)); !reflect.DeepEqual(got, want) {
+		t.Errorf("cells = %q, want %q", got, want)
+	}
+	got := touchedCells(before, b.requests[0])
+	wantTouched := map[[2]int]bool{{0, 0}: true, {0, 1}: true, {1, 0}: true, {1, 1}: true}
+	if !reflect.DeepEqual(got, wantTouched) {
+		t.Errorf("rewritten cells = %v, want %v", got, wantTouched)
+	}
+}
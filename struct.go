@@ -4,10 +4,11 @@
 package gdoctableapp
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	docs "google.golang.org/api/docs/v1"
-	drive "google.golang.org/api/drive/v3"
 	"google.golang.org/api/googleapi"
 )
 
@@ -22,38 +23,68 @@ type (
 		params Params // Input values
 		result Result // Output values
 
-		cell1stIndex int64
-		contents     [][]*tempColsContents
-		delCell      [][]*docs.Request
-		docTable     *docs.StructuralElement
-		docTables    []*docs.StructuralElement
-		parsedValues []tempCheckDupValues
-		requestBody  *docs.BatchUpdateDocumentRequest
-		srv          *docs.Service
-		srvDrive     *drive.Service
-		fields       googleapi.Field
+		applied       bool // When true, a batchUpdate or an upload of a file was already applied. Such calls are not retried on conflict.
+		backend       Backend
+		cell1stIndex  int64
+		chain         bool // When true, the requests are merged into pendingBody.
+		contents      [][]*tempColsContents
+		ctx           context.Context
+		delCell       [][]*docs.Request
+		docTable      *docs.StructuralElement
+		docTables     []*docs.StructuralElement
+		inlineObjects map[string]docs.InlineObject
+		withObjects   bool // When true, the inline objects are retrieved with the table for GetCells.
+		mergedCells   []MergedCell
+		parsedValues  []tempCheckDupValues
+		pendingBody   *docs.BatchUpdateDocumentRequest
+		pendingShift  bool // When true, pendingBody includes the requests which shift the indexes.
+		requestBody   *docs.BatchUpdateDocumentRequest
+		revisionID    string // Revision ID of the retrieved Document. This is used as RequiredRevisionId of batchUpdate.
+		fields        googleapi.Field
+		namedRanges   map[string]docs.NamedRanges
+		tableStale    bool // When true, the indexes of docTable were shifted.
 	}
 
 	// Result : Result from gdoctableapp
 	Result struct {
-		Tables           []Table       `json:"tables,omitempty"`
-		Values           [][]string    `json:"values,omitempty"`
-		ResponseFromAPIs []interface{} `json:"responseFromAPIs,omitempty"`
-		LibraryVersion   string        `json:"libraryVersion"`
-		Message          string        `json:"message,omitempty"`
+		Tables           []Table                            `json:"tables,omitempty"`
+		Values           [][]string                         `json:"values,omitempty"`
+		Cells            [][]Cell                           `json:"cells,omitempty"`
+		MergedCells      []MergedCell                       `json:"mergedCells,omitempty"` // Merged cells of the table of GetValues.
+		UpsertResult     *UpsertResult                      `json:"upsertResult,omitempty"`
+		DeletedRows      []int64                            `json:"deletedRows,omitempty"` // Indexes of the rows deleted by DeleteRowsWhere.
+		ResponseFromAPIs []interface{}                      `json:"responseFromAPIs,omitempty"`
+		RetryAttempts    []RetryAttempt                     `json:"retryAttempts,omitempty"`
+		Requests         []*docs.BatchUpdateDocumentRequest `json:"requests,omitempty"` // Request bodies planned by DryRun.
+		LibraryVersion   string                             `json:"libraryVersion"`
+		Message          string                             `json:"message,omitempty"`
 	}
 
 	// Params : Parameters inputted by users.
 	Params struct {
 		AppendRowRequest         *AppendRowRequest
+		Backend                  Backend `json:"-"`
+		CellStyleRequest         *CellStyleRequest
+		ColumnWidthsRequest      *ColumnWidthsRequest
 		Client                   *http.Client `json:"client"`
+		ConflictRetries          int          `json:"conflictRetries"`
 		CreateTableRequest       *CreateTableRequest
 		DeleteRowsColumnsRequest *DeleteRowsColumnsRequest
-		DocumentID               string          `json:"documentID"`
+		DocumentID               string `json:"documentID"`
+		InsertColumnsRequest     *InsertColumnsRequest
+		InsertRowsRequest        *InsertRowsRequest
+		MergeCellsRequest        *MergeCellsRequest
+		DryRunFlag               bool      `json:"dryRunFlag"`
+		Operations               []*Params `json:"operations"`
+		PinnedHeaderRows         int64     `json:"pinnedHeaderRows"`
+		SortRowsRequest          *SortRowsRequest
+		UpsertRowsRequest        *UpsertRowsRequest
 		ShowAPIResponseFlag      bool            `json:"showAPIResponseFlag"`
 		TableIdx                 int             `json:"tableIdx"`
 		ValuesArray              [][]interface{} `json:"valuesArray"`
+		ValuesInto               interface{}     `json:"-"`
 		ValuesObject             []ValueObject   `json:"valuesObject"`
+		RetryPolicy              *RetryPolicy    `json:"retryPolicy"`
 		ReplaceTextsToImagesP    struct {
 			FileID           string  `json:"fileID"`
 			ReplaceFromText  string  `json:"replaceFromText"`
@@ -62,20 +93,93 @@ type (
 			Width            float64 `json:"width"`
 			Height           float64 `json:"height"`
 		}
+		DeleteRowsWhereP struct {
+			Match func(row []string) bool `json:"-"`
+		}
+		ClearRangeP struct {
+			StartRowIndex    int64 `json:"startRowIndex"`
+			StartColumnIndex int64 `json:"startColumnIndex"`
+			EndRowIndex      int64 `json:"endRowIndex"`    // This row is not included.
+			EndColumnIndex   int64 `json:"endColumnIndex"` // This column is not included.
+		}
+		SyncTableP struct {
+			Values [][]string `json:"values"`
+		}
+		MoveP struct {
+			From int64 `json:"from"`
+			To   int64 `json:"to"`
+		}
+		FormatterP struct {
+			Table   Formatter           `json:"-"`
+			Columns map[int64]Formatter `json:"-"`
+		}
+		TableSelectorP struct {
+			By           string   `json:"by"`
+			Header       []string `json:"header"`
+			FirstCell    string   `json:"firstCell"`
+			Heading      string   `json:"heading"`
+			HeadingLevel int      `json:"headingLevel"`
+			HeadingID    string   `json:"headingID"`
+			NamedRange   string   `json:"namedRange"`
+		}
 		Works struct {
 			DoAppendRow                  bool `json:"doAppendRow"`
+			DoCellStyle                  bool `json:"doCellStyle"`
+			DoClearRange                 bool `json:"doClearRange"`
+			DoClearTable                 bool `json:"doClearTable"`
+			DoColumnWidths               bool `json:"doColumnWidths"`
 			DoCreateTable                bool `json:"doCreateTable"`
 			DoDeleteTable                bool `json:"doDeleteTable"`
 			DoDeleteRowsColumns          bool `json:"doDeleteRowsColumns"`
+			DoDeleteRowsWhere            bool `json:"doDeleteRowsWhere"`
+			DoGetCells                   bool `json:"doGetCells"`
 			DoGetValues                  bool `json:"doGetValues"`
 			DoGetTables                  bool `json:"doGetTables"`
+			DoInsertColumns              bool `json:"doInsertColumns"`
+			DoInsertRows                 bool `json:"doInsertRows"`
+			DoMergeCells                 bool `json:"doMergeCells"`
+			DoMoveColumn                 bool `json:"doMoveColumn"`
+			DoMoveRow                    bool `json:"doMoveRow"`
+			DoPinHeaderRows              bool `json:"doPinHeaderRows"`
+			DoUnmergeCells               bool `json:"doUnmergeCells"`
 			DoValuesArray                bool `json:"doValuesArray"`
 			DoValuesObject               bool `json:"doValuesObject"`
 			DoReplaceTextsToImagesByURL  bool `json:"doReplaceTextsToImagesByURL"`
 			DoReplaceTextsToImagesByFile bool `json:"doReplaceTextsToImagesByFile"`
+			DoSortRows                   bool `json:"doSortRows"`
+			DoSyncTable                  bool `json:"doSyncTable"`
+			DoUpsertRows                 bool `json:"doUpsertRows"`
 		}
 	}
 
+	// RetryPolicy : Policy for retrying the requests to Docs API and Drive API.
+	RetryPolicy struct {
+		MaxAttempts    int           `json:"maxAttempts"`    // Maximum number of attempts including the first request.
+		InitialBackoff time.Duration `json:"initialBackoff"` // Waiting time before the 2nd attempt.
+		MaxBackoff     time.Duration `json:"maxBackoff"`     // Maximum waiting time. When this is 0, the waiting time is not limited.
+		Multiplier     float64       `json:"multiplier"`     // Waiting time is multiplied by this for each attempt.
+		Jitter         float64       `json:"jitter"`         // Waiting time is randomly changed in the range of this ratio. e.g. 0.2 is +-20 %.
+		StatusCodes    []int         `json:"statusCodes"`    // HTTP status codes for retrying.
+	}
+
+	// RetryAttempt : Failed attempt which was retried.
+	RetryAttempt struct {
+		Op         string        `json:"op"`
+		Attempt    int           `json:"attempt"`
+		StatusCode int           `json:"statusCode"`
+		Error      string        `json:"error"`
+		Wait       time.Duration `json:"wait"`
+	}
+
+	// CellError : Error of converting the value of a cell.
+	CellError struct {
+		Row    int    `json:"row"`    // Row index of the table.
+		Column int    `json:"column"` // Column index of the table.
+		Field  string `json:"field"`  // Field name of the struct.
+		Value  string `json:"value"`
+		Err    error  `json:"-"`
+	}
+
 	// AppendRowRequest : Object for appending row and values to existing table.
 	AppendRowRequest struct {
 		Values [][]interface{} `json:"values"`
@@ -88,6 +192,57 @@ type (
 		Append  bool            `json:"append"`
 		Index   int64           `json:"index"`
 		Values  [][]interface{} `json:"values"`
+		// Widths of columns in order. Unit is PT. 0 is the evenly distributed width.
+		ColumnWidths []float64 `json:"columnWidths"`
+		// Number of the header rows repeated on each page.
+		PinnedHeaderRows int64 `json:"pinnedHeaderRows"`
+	}
+
+	// InsertRowsRequest : Object for inserting rows and values to existing table.
+	InsertRowsRequest struct {
+		Index       int64           `json:"index"`       // Row index of the reference row.
+		InsertBelow bool            `json:"insertBelow"` // When true, the rows are inserted below the reference row. When false, above it.
+		Count       int64           `json:"count"`       // Number of rows. When this is 0, the length of Values is used.
+		Values      [][]interface{} `json:"values"`      // Values of the inserted rows.
+	}
+
+	// InsertColumnsRequest : Object for inserting columns and values to existing table.
+	InsertColumnsRequest struct {
+		Index       int64           `json:"index"`       // Column index of the reference column.
+		InsertRight bool            `json:"insertRight"` // When true, the columns are inserted right of the reference column. When false, left of it.
+		Count       int64           `json:"count"`       // Number of columns. When this is 0, the maximum length of rows of Values is used.
+		Values      [][]interface{} `json:"values"`      // Values of the inserted columns. Values[0] is put to the 1st row.
+	}
+
+	// UpsertRowsRequest : Object for updating and appending rows by the key column.
+	UpsertRowsRequest struct {
+		KeyColumn  int64           `json:"keyColumn"`  // Column index of the key.
+		HeaderRows int64           `json:"headerRows"` // Number of the header rows which are not matched.
+		Values     [][]interface{} `json:"values"`     // Rows for upserting. Each row is required to include the key.
+	}
+
+	// UpsertResult : Numbers of the rows processed by UpsertRows.
+	UpsertResult struct {
+		Updated   int `json:"updated"`
+		Inserted  int `json:"inserted"`
+		Unchanged int `json:"unchanged"`
+	}
+
+	// SortRowsRequest : Object for sorting rows of a table.
+	SortRowsRequest struct {
+		HeaderRows int64     `json:"headerRows"` // Number of the header rows which are not sorted.
+		Keys       []SortKey `json:"keys"`       // Sort keys. The first key has the highest priority.
+	}
+
+	// SortKey : Key for sorting rows. The empty cells and the cells which cannot be parsed are put to the last.
+	// SortTypeString compares the texts byte by byte, so the upper case letters are put before the lower case letters,
+	// and "10" is put before "2". Please use CaseInsensitive and SortTypeNumber for them.
+	SortKey struct {
+		Column          int64  `json:"column"`          // Column index of the table.
+		Type            string `json:"type"`            // SortTypeString, SortTypeNumber or SortTypeDate. Default is SortTypeString.
+		Descending      bool   `json:"descending"`      // When true, the rows are sorted in descending order.
+		Layout          string `json:"layout"`          // Layout of the date. When this is empty, RFC3339 and some layouts are used.
+		CaseInsensitive bool   `json:"caseInsensitive"` // When true, the texts of SortTypeString are compared without the case.
 	}
 
 	// DeleteRowsColumnsRequest : Object for deleting rows and columns of a table.
@@ -103,12 +258,121 @@ type (
 			StartColumnIndex int64 `json:"startColumnIndex"`
 		} `json:"range"`
 		Values [][]interface{} `json:"values"`
+		Style  *TextStyle      `json:"style"`
+	}
+
+	// TextStyle : Style of the texts of cells. Only the fields which are not the zero values are updated.
+	TextStyle struct {
+		Bold            *bool   `json:"bold"`
+		Italic          *bool   `json:"italic"`
+		Underline       *bool   `json:"underline"`
+		Strikethrough   *bool   `json:"strikethrough"`
+		FontFamily      string  `json:"fontFamily"`
+		FontSize        float64 `json:"fontSize"`        // Unit is PT.
+		ForegroundColor string  `json:"foregroundColor"` // Hex color like "#ff0000".
+		Link            string  `json:"link"`            // URL of the link.
+	}
+
+	// StyledValue : Value with the style of text. This can be used as the value of cell.
+	StyledValue struct {
+		Value interface{} `json:"value"`
+		Style *TextStyle  `json:"style"`
+	}
+
+	// ColumnWidthsRequest : Object for setting the widths of columns of a table.
+	ColumnWidthsRequest struct {
+		Widths []ColumnWidth `json:"widths"`
+	}
+
+	// ColumnWidth : Width of the column.
+	ColumnWidth struct {
+		Column int64   `json:"column"` // Column index of the table.
+		Width  float64 `json:"width"`  // Unit is PT. When this is 0, the width is evenly distributed.
+	}
+
+	// MergeCellsRequest : Object for merging and unmerging cells of a table.
+	MergeCellsRequest struct {
+		Ranges []CellRange `json:"ranges"`
+	}
+
+	// CellRange : Range of cells of a table.
+	CellRange struct {
+		StartRowIndex    int64 `json:"startRowIndex"`
+		StartColumnIndex int64 `json:"startColumnIndex"`
+		Rows             int64 `json:"rows"`    // When this is 0, the range is to the last row.
+		Columns          int64 `json:"columns"` // When this is 0, the range is to the last column.
+	}
+
+	// MergedCell : Region of merged cells. Row and Column are the indexes of the head cell.
+	MergedCell struct {
+		Row        int64 `json:"row"`
+		Column     int64 `json:"column"`
+		RowSpan    int64 `json:"rowSpan"`
+		ColumnSpan int64 `json:"columnSpan"`
+	}
+
+	// CellStyleRequest : Object for setting the styles of cells.
+	// Header, Stripe and Ranges are used in this order. So the style of Ranges overwrites the others.
+	CellStyleRequest struct {
+		Header *CellStyle       `json:"header"` // Style of the first row.
+		Stripe *CellStyle       `json:"stripe"` // Style of the rows of 2, 4, 6,,, of row index. The header row is not included.
+		Ranges []CellStyleRange `json:"ranges"`
+	}
+
+	// CellStyleRange : Style for the range of cells.
+	CellStyleRange struct {
+		StartRowIndex    int64      `json:"startRowIndex"`
+		StartColumnIndex int64      `json:"startColumnIndex"`
+		Rows             int64      `json:"rows"`    // When this is 0, the range is to the last row.
+		Columns          int64      `json:"columns"` // When this is 0, the range is to the last column.
+		Style            *CellStyle `json:"style"`
+	}
+
+	// CellStyle : Style of cells. Only the fields which are not the zero values are updated.
+	CellStyle struct {
+		BackgroundColor  string   `json:"backgroundColor"`  // Hex color like "#ff0000".
+		BorderColor      string   `json:"borderColor"`      // Hex color of all borders. Default is "#000000".
+		BorderWidth      *float64 `json:"borderWidth"`      // Width of all borders. Unit is PT. Default is 1.
+		BorderDashStyle  string   `json:"borderDashStyle"`  // SOLID, DOT or DASH. Default is SOLID.
+		Padding          *float64 `json:"padding"`          // Padding of all sides. Unit is PT.
+		ContentAlignment string   `json:"contentAlignment"` // TOP, MIDDLE or BOTTOM.
+	}
+
+	// Cell : Rich content of a cell retrieved by GetCells.
+	Cell struct {
+		Row      int64         `json:"row"`
+		Column   int64         `json:"column"`
+		Text     string        `json:"text"` // Texts of the cell without the last newline. The inline objects and the nested tables are not included.
+		Elements []CellElement `json:"elements"`
+	}
+
+	// CellElement : Element of a cell. Type is CellElementText, CellElementInlineObject, CellElementTable or CellElementUnsupported.
+	CellElement struct {
+		Type         string        `json:"type"`
+		StartIndex   int64         `json:"startIndex"`
+		EndIndex     int64         `json:"endIndex"`
+		Text         string        `json:"text,omitempty"` // Content of the text run including the newlines.
+		Style        *TextStyle    `json:"style,omitempty"`
+		InlineObject *InlineObject `json:"inlineObject,omitempty"`
+		Table        [][]Cell      `json:"table,omitempty"` // Cells of the nested table.
+	}
+
+	// InlineObject : Inline object like an image in a cell.
+	InlineObject struct {
+		ObjectID    string  `json:"objectID"`
+		ContentURI  string  `json:"contentURI,omitempty"`
+		SourceURI   string  `json:"sourceURI,omitempty"`
+		Title       string  `json:"title,omitempty"`
+		Description string  `json:"description,omitempty"`
+		Width       float64 `json:"width,omitempty"`  // Unit is PT.
+		Height      float64 `json:"height,omitempty"` // Unit is PT.
 	}
 
 	// Table : Retrieved table.
 	Table struct {
-		Index         int64      `json:"index"`
-		Values        [][]string `json:"values"`
+		Index         int64        `json:"index"`
+		Values        [][]string   `json:"values"`
+		MergedCells   []MergedCell `json:"mergedCells,omitempty"`
 		TablePosition struct {
 			StartIndex int64 `json:"startIndex"`
 			EndIndex   int64 `json:"endIndex"`
@@ -139,5 +403,7 @@ type (
 		col     int64
 		content string
 		index   int64
+		style   *docs.TextStyle
+		fields  string
 	}
 )
@@ -0,0 +1,406 @@
+// Package gdoctableapp (style.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the styles of texts, cells and columns.
+package gdoctableapp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// Bool : Return the pointer of b. This is used for the fields of TextStyle.
+func Bool(b bool) *bool {
+	return &b
+}
+
+// Float64 : Return the pointer of f. This is used for the fields of CellStyle.
+func Float64(f float64) *float64 {
+	return &f
+}
+
+// HeaderHighlight : Preset of CellStyleRequest for highlighting the header row with backgroundColor.
+func HeaderHighlight(backgroundColor string) *CellStyleRequest {
+	return &CellStyleRequest{
+		Header: &CellStyle{BackgroundColor: backgroundColor},
+	}
+}
+
+// StripedRows : Preset of CellStyleRequest for striping the rows below the header row with backgroundColor.
+func StripedRows(backgroundColor string) *CellStyleRequest {
+	return &CellStyleRequest{
+		Stripe: &CellStyle{BackgroundColor: backgroundColor},
+	}
+}
+
+// setCellStyle : Set the styles of cells.
+func (o *obj) setCellStyle() error {
+	c := o.params.CellStyleRequest
+	if c == nil || (c.Header == nil && c.Stripe == nil && len(c.Ranges) == 0) {
+		return fmt.Errorf("No parameters for using SetCellStyle()")
+	}
+	table := o.docTable.Table
+	var ranges []CellStyleRange
+	if c.Header != nil {
+		ranges = append(ranges, CellStyleRange{Rows: 1, Style: c.Header})
+	}
+	if c.Stripe != nil {
+		for i := int64(2); i < table.Rows; i += 2 {
+			ranges = append(ranges, CellStyleRange{StartRowIndex: i, Rows: 1, Style: c.Stripe})
+		}
+	}
+	ranges = append(ranges, c.Ranges...)
+	br := &docs.BatchUpdateDocumentRequest{}
+	for _, r := range ranges {
+		if r.Style == nil {
+			return fmt.Errorf("Style of the range is not set")
+		}
+		tableRange, err := o.createTableRange(CellRange{
+			StartRowIndex:    r.StartRowIndex,
+			StartColumnIndex: r.StartColumnIndex,
+			Rows:             r.Rows,
+			Columns:          r.Columns,
+		})
+		if err != nil {
+			return err
+		}
+		style, fields, err := r.Style.toDocs()
+		if err != nil {
+			return err
+		}
+		if style == nil {
+			continue
+		}
+		br.Requests = append(br.Requests, &docs.Request{
+			UpdateTableCellStyle: &docs.UpdateTableCellStyleRequest{
+				TableRange:     tableRange,
+				TableCellStyle: style,
+				Fields:         fields,
+			},
+		})
+	}
+	if len(br.Requests) == 0 {
+		return nil
+	}
+	o.requestBody = br
+	if err := o.documentbatchUpdate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// setColumnWidths : Set the widths of columns of a table.
+func (o *obj) setColumnWidths() error {
+	if o.params.ColumnWidthsRequest == nil || len(o.params.ColumnWidthsRequest.Widths) == 0 {
+		return fmt.Errorf("No parameters for using SetColumnWidths()")
+	}
+	for _, w := range o.params.ColumnWidthsRequest.Widths {
+		if w.Column < 0 || w.Column >= o.docTable.Table.Columns {
+			return fmt.Errorf("Column %d is outside of the table", w.Column)
+		}
+	}
+	requests, err := createColumnWidthRequests(o.docTable.StartIndex, o.params.ColumnWidthsRequest.Widths)
+	if err != nil {
+		return err
+	}
+	o.requestBody = &docs.BatchUpdateDocumentRequest{Requests: requests}
+	if err := o.documentbatchUpdate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// createColumnWidthRequests : Create UpdateTableColumnPropertiesRequests for the table of tableStartIndex.
+// The columns with the same width are put into one request.
+func createColumnWidthRequests(tableStartIndex int64, widths []ColumnWidth) ([]*docs.Request, error) {
+	var requests []*docs.Request
+	indexes := map[float64]int{}
+	for _, w := range widths {
+		if err := checkColumnWidth(w.Width); err != nil {
+			return nil, err
+		}
+		if i, ok := indexes[w.Width]; ok {
+			r := requests[i].UpdateTableColumnProperties
+			r.ColumnIndices = append(r.ColumnIndices, w.Column)
+			continue
+		}
+		r := &docs.UpdateTableColumnPropertiesRequest{
+			TableStartLocation: &docs.Location{
+				Index: tableStartIndex,
+			},
+			ColumnIndices: []int64{w.Column},
+		}
+		if w.Width > 0 {
+			r.TableColumnProperties = &docs.TableColumnProperties{
+				WidthType: "FIXED_WIDTH",
+				Width:     &docs.Dimension{Magnitude: w.Width, Unit: "PT"},
+			}
+			r.Fields = "width,widthType"
+		} else {
+			r.TableColumnProperties = &docs.TableColumnProperties{
+				WidthType: "EVENLY_DISTRIBUTED",
+			}
+			r.Fields = "widthType"
+		}
+		indexes[w.Width] = len(requests)
+		requests = append(requests, &docs.Request{UpdateTableColumnProperties: r})
+	}
+	return requests, nil
+}
+
+// pinHeaderRows : Pin the header rows of a table. The pinned rows are repeated on each page.
+func (o *obj) pinHeaderRows() error {
+	n := o.params.PinnedHeaderRows
+	if n < 0 || n > o.docTable.Table.Rows {
+		return fmt.Errorf("Number of header rows must be from 0 to the rows of the table")
+	}
+	o.requestBody = &docs.BatchUpdateDocumentRequest{
+		Requests: []*docs.Request{createPinTableHeaderRowsRequest(o.docTable.StartIndex, n)},
+	}
+	if err := o.documentbatchUpdate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// createPinTableHeaderRowsRequest : Create PinTableHeaderRowsRequest. When n is 0, the header rows are unpinned.
+func createPinTableHeaderRowsRequest(tableStartIndex, n int64) *docs.Request {
+	return &docs.Request{
+		PinTableHeaderRows: &docs.PinTableHeaderRowsRequest{
+			TableStartLocation: &docs.Location{
+				Index: tableStartIndex,
+			},
+			PinnedHeaderRowsCount: n,
+			ForceSendFields:       []string{"PinnedHeaderRowsCount"},
+		},
+	}
+}
+
+// checkColumnWidth : Check the width of column. The minimum width of Docs API is 5 PT.
+func checkColumnWidth(width float64) error {
+	if width < 0 || (width > 0 && width < 5) {
+		return fmt.Errorf("Width of column must be 5 PT or more")
+	}
+	return nil
+}
+
+// toDocs : Convert CellStyle to the style and the fields for UpdateTableCellStyleRequest.
+func (s *CellStyle) toDocs() (*docs.TableCellStyle, string, error) {
+	cs := &docs.TableCellStyle{}
+	var fields []string
+	if s.BackgroundColor != "" {
+		c, err := parseColor(s.BackgroundColor)
+		if err != nil {
+			return nil, "", err
+		}
+		cs.BackgroundColor = c
+		fields = append(fields, "backgroundColor")
+	}
+	if s.BorderColor != "" || s.BorderWidth != nil || s.BorderDashStyle != "" {
+		color := s.BorderColor
+		if color == "" {
+			color = "#000000"
+		}
+		c, err := parseColor(color)
+		if err != nil {
+			return nil, "", err
+		}
+		width := 1.0
+		if s.BorderWidth != nil {
+			width = *s.BorderWidth
+		}
+		if width < 0 {
+			return nil, "", fmt.Errorf("BorderWidth must be 0 or more")
+		}
+		dashStyle := strings.ToUpper(s.BorderDashStyle)
+		if dashStyle == "" {
+			dashStyle = "SOLID"
+		}
+		if dashStyle != "SOLID" && dashStyle != "DOT" && dashStyle != "DASH" {
+			return nil, "", fmt.Errorf("Invalid BorderDashStyle: %q", s.BorderDashStyle)
+		}
+		border := &docs.TableCellBorder{
+			Color:     c,
+			DashStyle: dashStyle,
+			Width:     &docs.Dimension{Magnitude: width, Unit: "PT", ForceSendFields: []string{"Magnitude"}},
+		}
+		cs.BorderTop, cs.BorderBottom, cs.BorderLeft, cs.BorderRight = border, border, border, border
+		fields = append(fields, "borderTop", "borderBottom", "borderLeft", "borderRight")
+	}
+	if s.Padding != nil {
+		if *s.Padding < 0 {
+			return nil, "", fmt.Errorf("Padding must be 0 or more")
+		}
+		padding := &docs.Dimension{Magnitude: *s.Padding, Unit: "PT", ForceSendFields: []string{"Magnitude"}}
+		cs.PaddingTop, cs.PaddingBottom, cs.PaddingLeft, cs.PaddingRight = padding, padding, padding, padding
+		fields = append(fields, "paddingTop", "paddingBottom", "paddingLeft", "paddingRight")
+	}
+	if s.ContentAlignment != "" {
+		alignment := strings.ToUpper(s.ContentAlignment)
+		if alignment != "TOP" && alignment != "MIDDLE" && alignment != "BOTTOM" {
+			return nil, "", fmt.Errorf("Invalid ContentAlignment: %q", s.ContentAlignment)
+		}
+		cs.ContentAlignment = alignment
+		fields = append(fields, "contentAlignment")
+	}
+	if len(fields) == 0 {
+		return nil, "", nil
+	}
+	return cs, strings.Join(fields, ","), nil
+}
+
+// parseValue : Convert the value of the column to string, and return the style of text for the value.
+// When the value is StyledValue, the style of the value is merged into style.
+func (o *obj) parseValue(v interface{}, col int64, style *TextStyle) (string, *docs.TextStyle, string, error) {
+	switch sv := v.(type) {
+	case StyledValue:
+		v = sv.Value
+		style = mergeTextStyle(style, sv.Style)
+	case *StyledValue:
+		if sv != nil {
+			v = sv.Value
+			style = mergeTextStyle(style, sv.Style)
+		}
+	}
+	content, err := o.formatValue(v, col)
+	if err != nil {
+		return "", nil, "", err
+	}
+	if style == nil {
+		return content, nil, "", nil
+	}
+	ts, fields, err := style.toDocs()
+	if err != nil {
+		return "", nil, "", err
+	}
+	return content, ts, fields, nil
+}
+
+// mergeTextStyle : Merge s into base. The fields of s are used when both are set.
+func mergeTextStyle(base, s *TextStyle) *TextStyle {
+	if base == nil {
+		return s
+	}
+	if s == nil {
+		return base
+	}
+	m := *base
+	if s.Bold != nil {
+		m.Bold = s.Bold
+	}
+	if s.Italic != nil {
+		m.Italic = s.Italic
+	}
+	if s.Underline != nil {
+		m.Underline = s.Underline
+	}
+	if s.Strikethrough != nil {
+		m.Strikethrough = s.Strikethrough
+	}
+	if s.FontFamily != "" {
+		m.FontFamily = s.FontFamily
+	}
+	if s.FontSize > 0 {
+		m.FontSize = s.FontSize
+	}
+	if s.ForegroundColor != "" {
+		m.ForegroundColor = s.ForegroundColor
+	}
+	if s.Link != "" {
+		m.Link = s.Link
+	}
+	return &m
+}
+
+// toDocs : Convert TextStyle to the style and the fields for UpdateTextStyleRequest.
+func (s *TextStyle) toDocs() (*docs.TextStyle, string, error) {
+	ts := &docs.TextStyle{}
+	var fields []string
+	if s.Bold != nil {
+		ts.Bold = *s.Bold
+		ts.ForceSendFields = append(ts.ForceSendFields, "Bold")
+		fields = append(fields, "bold")
+	}
+	if s.Italic != nil {
+		ts.Italic = *s.Italic
+		ts.ForceSendFields = append(ts.ForceSendFields, "Italic")
+		fields = append(fields, "italic")
+	}
+	if s.Underline != nil {
+		ts.Underline = *s.Underline
+		ts.ForceSendFields = append(ts.ForceSendFields, "Underline")
+		fields = append(fields, "underline")
+	}
+	if s.Strikethrough != nil {
+		ts.Strikethrough = *s.Strikethrough
+		ts.ForceSendFields = append(ts.ForceSendFields, "Strikethrough")
+		fields = append(fields, "strikethrough")
+	}
+	if s.FontFamily != "" {
+		ts.WeightedFontFamily = &docs.WeightedFontFamily{FontFamily: s.FontFamily}
+		fields = append(fields, "weightedFontFamily")
+	}
+	if s.FontSize < 0 {
+		return nil, "", fmt.Errorf("FontSize must be 0 or more")
+	}
+	if s.FontSize > 0 {
+		ts.FontSize = &docs.Dimension{Magnitude: s.FontSize, Unit: "PT"}
+		fields = append(fields, "fontSize")
+	}
+	if s.ForegroundColor != "" {
+		c, err := parseColor(s.ForegroundColor)
+		if err != nil {
+			return nil, "", err
+		}
+		ts.ForegroundColor = c
+		fields = append(fields, "foregroundColor")
+	}
+	if s.Link != "" {
+		ts.Link = &docs.Link{Url: s.Link}
+		fields = append(fields, "link")
+	}
+	if len(fields) == 0 {
+		return nil, "", nil
+	}
+	return ts, strings.Join(fields, ","), nil
+}
+
+// parseColor : Convert the hex color like "#ff0000" or "#f00" to OptionalColor.
+func parseColor(color string) (*docs.OptionalColor, error) {
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return nil, fmt.Errorf("Invalid color: %q", color)
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid color: %q", color)
+	}
+	return &docs.OptionalColor{
+		Color: &docs.Color{
+			RgbColor: &docs.RgbColor{
+				Red:   float64(n>>16&0xff) / 255,
+				Green: float64(n>>8&0xff) / 255,
+				Blue:  float64(n&0xff) / 255,
+			},
+		},
+	}, nil
+}
+
+// createUpdateTextStyleRequest : Create UpdateTextStyleRequest.
+func createUpdateTextStyleRequest(startIndex, endIndex int64, style *docs.TextStyle, fields string) *docs.Request {
+	return &docs.Request{
+		UpdateTextStyle: &docs.UpdateTextStyleRequest{
+			Range: &docs.Range{
+				StartIndex: startIndex,
+				EndIndex:   endIndex,
+			},
+			TextStyle: style,
+			Fields:    fields,
+		},
+	}
+}
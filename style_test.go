@@ -0,0 +1,309 @@
+package gdoctableapp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	docs "google.golang.org/api/docs/v1"
+)
+
+// cellStyleRanges : Return the ranges and the fields of UpdateTableCellStyleRequests like "1,2 3x4 backgroundColor".
+func cellStyleRanges(t *testing.T, requests []*docs.Request) []string {
+	t.Helper()
+	var res []string
+	for _, r := range requests {
+		u := r.UpdateTableCellStyle
+		if u == nil {
+			t.Fatalf("request is not UpdateTableCellStyle: %q", requestNames([]*docs.Request{r}))
+		}
+		loc := u.TableRange.TableCellLocation
+		if loc.TableStartLocation.Index != 2 {
+			t.Errorf("table start index = %d, want 2", loc.TableStartLocation.Index)
+		}
+		res = append(res, fmt.Sprintf("%d,%d %dx%d %s", loc.RowIndex, loc.ColumnIndex, u.TableRange.RowSpan, u.TableRange.ColumnSpan, u.Fields))
+	}
+	return res
+}
+
+func TestSetCellStyle(t *testing.T) {
+	values := make([][]interface{}, 5)
+	for i := range values {
+		values[i] = []interface{}{"a", "b", "c"}
+	}
+	f := newTestTable(t, values)
+	b := &hookBackend{FakeBackend: f}
+	c := &CellStyleRequest{
+		Header: &CellStyle{BackgroundColor: "#f00"},
+		Stripe: &CellStyle{BackgroundColor: "#00ff00"},
+		Ranges: []CellStyleRange{
+			{StartRowIndex: 1, StartColumnIndex: 1, Rows: 2, Style: &CellStyle{BorderWidth: Float64(0), Padding: Float64(2), ContentAlignment: "middle"}},
+			{StartRowIndex: 4, Style: &CellStyle{}},
+		},
+	}
+	if _, err := New().Docs("doc").SetBackend(b).SetCellStyle(c).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.requests) != 1 {
+		t.Fatalf("batchUpdate was requested %d times, want 1", len(b.requests))
+	}
+	requests := b.requests[0]
+	// The stripes are put to every other row below the header row, and the range without the style is ignored.
+	want := []string{
+		"0,0 1x3 backgroundColor",
+		"2,0 1x3 backgroundColor",
+		"4,0 1x3 backgroundColor",
+		"1,1 2x2 borderTop,borderBottom,borderLeft,borderRight,paddingTop,paddingBottom,paddingLeft,paddingRight,contentAlignment",
+	}
+	if got := cellStyleRanges(t, requests); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ranges = %q, want %q", got, want)
+	}
+	if got := requests[0].UpdateTableCellStyle.TableCellStyle.BackgroundColor.Color.RgbColor; !reflect.DeepEqual(*got, docs.RgbColor{Red: 1}) {
+		t.Errorf("header color = %+v, want red", got)
+	}
+	if got := requests[1].UpdateTableCellStyle.TableCellStyle.BackgroundColor.Color.RgbColor; !reflect.DeepEqual(*got, docs.RgbColor{Green: 1}) {
+		t.Errorf("stripe color = %+v, want green", got)
+	}
+	s := requests[3].UpdateTableCellStyle.TableCellStyle
+	for _, border := range []*docs.TableCellBorder{s.BorderTop, s.BorderBottom, s.BorderLeft, s.BorderRight} {
+		// The border of the width 0 is sent with the default color and dash style.
+		if border.DashStyle != "SOLID" || !reflect.DeepEqual(*border.Color.Color.RgbColor, docs.RgbColor{}) ||
+			border.Width.Magnitude != 0 || border.Width.Unit != "PT" || !reflect.DeepEqual(border.Width.ForceSendFields, []string{"Magnitude"}) {
+			t.Errorf("border = %+v", border)
+		}
+	}
+	for _, padding := range []*docs.Dimension{s.PaddingTop, s.PaddingBottom, s.PaddingLeft, s.PaddingRight} {
+		if padding.Magnitude != 2 || padding.Unit != "PT" {
+			t.Errorf("padding = %+v", padding)
+		}
+	}
+	if s.ContentAlignment != "MIDDLE" {
+		t.Errorf("content alignment = %q, want MIDDLE", s.ContentAlignment)
+	}
+	var colors []string
+	for _, row := range cellSignatures(testTable(t, f)) {
+		colors = append(colors, strings.Join(row, " "))
+	}
+	wantColors := []string{"a[1 0 0] b[1 0 0] c[1 0 0]", "a b c", "a[0 1 0] b[0 1 0] c[0 1 0]", "a b c", "a[0 1 0] b[0 1 0] c[0 1 0]"}
+	if !reflect.DeepEqual(colors, wantColors) {
+		t.Errorf("cells = %q, want %q", colors, wantColors)
+	}
+}
+
+func TestSetCellStyleInvalidParameters(t *testing.T) {
+	// The stripe is put to the 3rd row.
+	f := newTestTable(t, [][]interface{}{{"a", "b"}, {"c", "d"}, {"e", "f"}})
+	b := &hookBackend{FakeBackend: f}
+	for _, c := range []struct {
+		request *CellStyleRequest
+		err     string
+	}{
+		{nil, "No parameters"},
+		{&CellStyleRequest{}, "No parameters"},
+		{HeaderHighlight("#ff"), `Invalid color: "#ff"`},
+		{StripedRows("red"), `Invalid color: "red"`},
+		{&CellStyleRequest{Ranges: []CellStyleRange{{}}}, "Style of the range is not set"},
+		{&CellStyleRequest{Ranges: []CellStyleRange{{StartRowIndex: 3, Style: &CellStyle{Padding: Float64(1)}}}}, "outside of the table"},
+		{&CellStyleRequest{Ranges: []CellStyleRange{{Columns: 3, Style: &CellStyle{Padding: Float64(1)}}}}, "outside of the table"},
+		{&CellStyleRequest{Header: &CellStyle{Padding: Float64(-1)}}, "Padding must be 0 or more"},
+		{&CellStyleRequest{Header: &CellStyle{BorderWidth: Float64(-1)}}, "BorderWidth must be 0 or more"},
+		{&CellStyleRequest{Header: &CellStyle{BorderDashStyle: "DOTTED"}}, `Invalid BorderDashStyle: "DOTTED"`},
+		{&CellStyleRequest{Header: &CellStyle{ContentAlignment: "CENTER"}}, `Invalid ContentAlignment: "CENTER"`},
+		// The valid range is not applied when the other range is invalid.
+		{&CellStyleRequest{Header: HeaderHighlight("#fff").Header, Stripe: &CellStyle{ContentAlignment: "LEFT"}}, "Invalid ContentAlignment"},
+	} {
+		_, err := New().Docs("doc").SetBackend(b).SetCellStyle(c.request).Do(nil)
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("err = %v, want the error including %q", err, c.err)
+		}
+	}
+	if b.batches != 0 {
+		t.Errorf("batchUpdate was requested %d times, want 0", b.batches)
+	}
+}
+
+// columnWidthTypes : Return the width types and the widths of the columns of the table like "FIXED_WIDTH 100".
+func columnWidthTypes(table *docs.Table) []string {
+	var res []string
+	for _, p := range table.TableStyle.TableColumnProperties {
+		s := p.WidthType
+		if p.Width != nil {
+			s += fmt.Sprintf(" %g", p.Width.Magnitude)
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+func TestSetColumnWidths(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b", "c"}})
+	b := &hookBackend{FakeBackend: f}
+	c := &ColumnWidthsRequest{Widths: []ColumnWidth{{Column: 0, Width: 100}, {Column: 1, Width: 0}, {Column: 2, Width: 100}}}
+	if _, err := New().Docs("doc").SetBackend(b).SetColumnWidths(c).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	// The columns of the same width are put into one request.
+	want := []*docs.Request{
+		{UpdateTableColumnProperties: &docs.UpdateTableColumnPropertiesRequest{
+			TableStartLocation:    &docs.Location{Index: 2},
+			ColumnIndices:         []int64{0, 2},
+			TableColumnProperties: &docs.TableColumnProperties{WidthType: "FIXED_WIDTH", Width: &docs.Dimension{Magnitude: 100, Unit: "PT"}},
+			Fields:                "width,widthType",
+		}},
+		{UpdateTableColumnProperties: &docs.UpdateTableColumnPropertiesRequest{
+			TableStartLocation:    &docs.Location{Index: 2},
+			ColumnIndices:         []int64{1},
+			TableColumnProperties: &docs.TableColumnProperties{WidthType: "EVENLY_DISTRIBUTED"},
+			Fields:                "widthType",
+		}},
+	}
+	if len(b.requests) != 1 || !reflect.DeepEqual(b.requests[0], want) {
+		t.Fatalf("requests = %s, want %s", requestsJSON(b.requests), requestsJSON([][]*docs.Request{want}))
+	}
+	if got, want := columnWidthTypes(testTable(t, f)), []string{"FIXED_WIDTH 100", "EVENLY_DISTRIBUTED", "FIXED_WIDTH 100"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("columns = %q, want %q", got, want)
+	}
+}
+
+func TestSetColumnWidthsInvalidParameters(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}})
+	b := &hookBackend{FakeBackend: f}
+	for _, c := range []*ColumnWidthsRequest{
+		nil,
+		{},
+		{Widths: []ColumnWidth{{Column: 2, Width: 100}}},
+		{Widths: []ColumnWidth{{Column: -1, Width: 100}}},
+		{Widths: []ColumnWidth{{Column: 0, Width: 100}, {Column: 1, Width: 4.9}}},
+		{Widths: []ColumnWidth{{Column: 0, Width: -1}}},
+	} {
+		if _, err := New().Docs("doc").SetBackend(b).SetColumnWidths(c).Do(nil); err == nil {
+			t.Errorf("no error for %+v", c)
+		}
+	}
+	if b.batches != 0 {
+		t.Errorf("batchUpdate was requested %d times, want 0", b.batches)
+	}
+}
+
+// newTextDocument : Create the Document of "doc" including a paragraph of "text".
+func newTextDocument(t *testing.T) *FakeBackend {
+	t.Helper()
+	f := NewFakeBackend()
+	f.NewDocument("doc")
+	if err := fakeApply(f, insertText(1, "text\n")); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestCreateTableWithColumnWidths(t *testing.T) {
+	for _, c := range []*CreateTableRequest{
+		{Rows: 2, Columns: 3, Index: 6, Values: [][]interface{}{{"a", "b", "c"}}, ColumnWidths: []float64{50, 0}},
+		{Rows: 2, Columns: 3, Append: true, Values: [][]interface{}{{"a", "b", "c"}}, ColumnWidths: []float64{50, 0}},
+	} {
+		p := func() *Params { return New().Docs("doc").CreateTable(c) }
+		newTable := func() *FakeBackend { return newTextDocument(t) }
+		// The requests of DryRun use the same location of the table as the applied requests.
+		f := assertDryRun(t, newTable, p)
+		var table *docs.StructuralElement
+		for _, e := range f.Document("doc").Body.Content {
+			if e.Table != nil {
+				table = e
+			}
+		}
+		if table.StartIndex != 7 {
+			t.Errorf("table starts at %d, want 7", table.StartIndex)
+		}
+		if got, want := columnWidthTypes(table.Table), []string{"FIXED_WIDTH 50", "EVENLY_DISTRIBUTED", "EVENLY_DISTRIBUTED"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("columns = %q, want %q", got, want)
+		}
+		assertValues(t, testValues(t, f), [][]string{{"a", "b", "c"}, {"", "", ""}})
+	}
+	for _, c := range []*CreateTableRequest{
+		{Rows: 1, Columns: 2, Index: 1, ColumnWidths: []float64{50, 50, 50}},
+		{Rows: 1, Columns: 2, Index: 1, ColumnWidths: []float64{50, 1}},
+	} {
+		b := &hookBackend{FakeBackend: newTextDocument(t)}
+		if _, err := New().Docs("doc").SetBackend(b).CreateTable(c).Do(nil); err == nil {
+			t.Errorf("no error for %+v", c)
+		}
+		if b.batches != 0 {
+			t.Errorf("batchUpdate was requested %d times, want 0", b.batches)
+		}
+	}
+}
+
+// tableHeaders : Return whether each row of the table is pinned as the header row.
+func tableHeaders(table *docs.Table) []bool {
+	var res []bool
+	for _, row := range table.TableRows {
+		res = append(res, row.TableRowStyle != nil && row.TableRowStyle.TableHeader)
+	}
+	return res
+}
+
+func TestPinHeaderRows(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a"}, {"b"}, {"c"}})
+	b := &hookBackend{FakeBackend: f}
+	if _, err := New().Docs("doc").SetBackend(b).PinHeaderRows(2).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tableHeaders(testTable(t, f)), []bool{true, true, false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("header rows = %v, want %v", got, want)
+	}
+	// 0 unpins the header rows. So PinnedHeaderRowsCount of 0 is required to be sent.
+	if _, err := New().Docs("doc").SetBackend(b).PinHeaderRows(0).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tableHeaders(testTable(t, f)), []bool{false, false, false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("header rows = %v, want %v", got, want)
+	}
+	want := [][]*docs.Request{
+		{{PinTableHeaderRows: &docs.PinTableHeaderRowsRequest{TableStartLocation: &docs.Location{Index: 2}, PinnedHeaderRowsCount: 2, ForceSendFields: []string{"PinnedHeaderRowsCount"}}}},
+		{{PinTableHeaderRows: &docs.PinTableHeaderRowsRequest{TableStartLocation: &docs.Location{Index: 2}, ForceSendFields: []string{"PinnedHeaderRowsCount"}}}},
+	}
+	if !reflect.DeepEqual(b.requests, want) {
+		t.Errorf("requests = %s, want %s", requestsJSON(b.requests), requestsJSON(want))
+	}
+	for _, n := range []int64{-1, 4} {
+		if _, err := New().Docs("doc").SetBackend(b).PinHeaderRows(n).Do(nil); err == nil {
+			t.Errorf("no error for %d rows", n)
+		}
+	}
+	if b.batches != 2 {
+		t.Errorf("batchUpdate was requested %d times, want 2", b.batches)
+	}
+}
+
+func TestCreateTableWithPinnedHeaderRows(t *testing.T) {
+	for _, c := range []*CreateTableRequest{
+		{Rows: 3, Columns: 1, Index: 6, Values: [][]interface{}{{"a"}}, PinnedHeaderRows: 1},
+		{Rows: 3, Columns: 1, Append: true, Values: [][]interface{}{{"a"}}, PinnedHeaderRows: 1},
+	} {
+		f := newTextDocument(t)
+		b := &hookBackend{FakeBackend: f}
+		if _, err := New().Docs("doc").SetBackend(b).CreateTable(c).Do(nil); err != nil {
+			t.Fatal(err)
+		}
+		// The table is inserted after the newline of index, so the table starts at index + 1.
+		table := testTable(t, f)
+		requests := b.requests[len(b.requests)-1]
+		if got := requests[len(requests)-1].PinTableHeaderRows.TableStartLocation.Index; got != 7 {
+			t.Errorf("table start location of PinTableHeaderRows = %d, want 7", got)
+		}
+		if got, want := tableHeaders(table), []bool{true, false, false}; !reflect.DeepEqual(got, want) {
+			t.Errorf("header rows = %v, want %v", got, want)
+		}
+		assertValues(t, testValues(t, f), [][]string{{"a"}, {""}, {""}})
+	}
+	for _, n := range []int64{-1, 3} {
+		b := &hookBackend{FakeBackend: newTextDocument(t)}
+		if _, err := New().Docs("doc").SetBackend(b).CreateTable(&CreateTableRequest{Rows: 2, Columns: 1, Index: 1, PinnedHeaderRows: n}).Do(nil); err == nil {
+			t.Errorf("no error for %d rows", n)
+		}
+		if b.batches != 0 {
+			t.Errorf("batchUpdate was requested %d times, want 0", b.batches)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Package gdoctableapp (sync.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the methods for synchronizing a table with values.
+package gdoctableapp
+
+import (
+	"fmt"
+)
+
+// syncTable : Synchronize the table with the values of SyncTableP.
+// Only the cells whose values are different are rewritten. The rows and columns are appended by setValuesMain,
+// and the extra rows and columns are deleted from the last of the table after the cells were rewritten.
+func (o *obj) syncTable() error {
+	desired := o.params.SyncTableP.Values
+	var rows, cols int64
+	rows = int64(len(desired))
+	for _, row := range desired {
+		if cols < int64(len(row)) {
+			cols = int64(len(row))
+		}
+	}
+	if rows == 0 || cols == 0 {
+		return fmt.Errorf("Values for using SyncTable() are empty. When you want to delete the table, please use DeleteTable()")
+	}
+	values, err := o.getValues()
+	if err != nil {
+		return err
+	}
+	table := o.docTable.Table
+	var vos []ValueObject
+	for i := int64(0); i < rows; i++ {
+		for j := int64(0); j < cols; j++ {
+			var v string
+			if j < int64(len(desired[i])) {
+				v = desired[i][j]
+			}
+			if i < table.Rows && j < table.Columns && v == values[i][j] {
+				continue
+			}
+			vo := ValueObject{Values: [][]interface{}{{v}}}
+			vo.Range.StartRowIndex = i
+			vo.Range.StartColumnIndex = j
+			vos = append(vos, vo)
+		}
+	}
+	if len(vos) > 0 {
+		o.params.ValuesObject = vos
+		if err := o.setValuesMain(); err != nil {
+			return err
+		}
+	}
+	d := &DeleteRowsColumnsRequest{}
+	for i := rows; i < table.Rows; i++ {
+		d.Rows = append(d.Rows, i)
+	}
+	for j := cols; j < table.Columns; j++ {
+		d.Columns = append(d.Columns, j)
+	}
+	if len(d.Rows) == 0 && len(d.Columns) == 0 {
+		return nil
+	}
+	o.params.DeleteRowsColumnsRequest = d
+	return o.deleteRowsColumns()
+}
@@ -0,0 +1,103 @@
+package gdoctableapp
+
+import (
+	"testing"
+)
+
+func TestSyncTable(t *testing.T) {
+	// The cell of "a" is bold, the cell of "d" is italic, and the 1st row is highlighted.
+	newTable := func(t *testing.T) *FakeBackend {
+		f := newTestTable(t, [][]interface{}{
+			{StyledValue{Value: "a", Style: &TextStyle{Bold: Bool(true)}}, "b"},
+			{"c", StyledValue{Value: "d", Style: &TextStyle{Italic: Bool(true)}}},
+		})
+		if _, err := New().Docs("doc").SetBackend(f).SetCellStyle(HeaderHighlight("#ff0000")).Do(nil); err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	old := [][]string{{"a", "b"}, {"c", "d"}}
+	initial := cellSignatures(testTable(t, newTable(t)))
+	if want := "*a*[1 0 0]"; initial[0][0] != want {
+		t.Fatalf("cell (0, 0) = %q, want %q", initial[0][0], want)
+	}
+	for _, c := range []struct {
+		name         string
+		values, want [][]string
+	}{
+		{
+			name:   "same",
+			values: [][]string{{"a", "b"}, {"c", "d"}},
+			want:   [][]string{{"a", "b"}, {"c", "d"}},
+		},
+		{
+			name:   "changed values",
+			values: [][]string{{"a", "B"}, {"C", "d"}},
+			want:   [][]string{{"a", "B"}, {"C", "d"}},
+		},
+		{
+			name:   "more rows and columns",
+			values: [][]string{{"a", "b", "x"}, {"c", "d", "y"}, {"e", "f", "g"}},
+			want:   [][]string{{"a", "b", "x"}, {"c", "d", "y"}, {"e", "f", "g"}},
+		},
+		{
+			name:   "more rows of empty cells",
+			values: [][]string{{"a", "b"}, {"c", "d"}, {"", ""}},
+			want:   [][]string{{"a", "b"}, {"c", "d"}, {"", ""}},
+		},
+		{
+			name:   "fewer rows and columns",
+			values: [][]string{{"a"}},
+			want:   [][]string{{"a"}},
+		},
+		{
+			name:   "fewer rows",
+			values: [][]string{{"a", "b"}},
+			want:   [][]string{{"a", "b"}},
+		},
+		{
+			name:   "more rows and fewer columns",
+			values: [][]string{{"a"}, {"c"}, {"e"}},
+			want:   [][]string{{"a"}, {"c"}, {"e"}},
+		},
+		{
+			name:   "fewer rows and more columns",
+			values: [][]string{{"x", "b", "y"}},
+			want:   [][]string{{"x", "b", "y"}},
+		},
+		{
+			name:   "short row",
+			values: [][]string{{"a", "b", "x"}, {"c"}},
+			want:   [][]string{{"a", "b", "x"}, {"c", "", ""}},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			f := newTable(t)
+			if _, err := New().Docs("doc").SetBackend(f).SyncTable(c.values).Do(nil); err != nil {
+				t.Fatal(err)
+			}
+			assertValues(t, testValues(t, f), c.want)
+			got := cellSignatures(testTable(t, f))
+			for i := range old {
+				for j := range old[i] {
+					if i >= len(c.want) || j >= len(c.want[i]) || c.want[i][j] != old[i][j] {
+						continue
+					}
+					if got[i][j] != initial[i][j] {
+						t.Errorf("unchanged cell (%d, %d) = %q, want %q", i, j, got[i][j], initial[i][j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSyncTableWithEmptyValues(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"a", "b"}})
+	for _, values := range [][][]string{nil, {{}}} {
+		if _, err := New().Docs("doc").SetBackend(f).SyncTable(values).Do(nil); err == nil {
+			t.Errorf("no error for %q", values)
+		}
+	}
+	assertValues(t, testValues(t, f), [][]string{{"a", "b"}})
+}
@@ -0,0 +1,89 @@
+// Package gdoctableapp (upsert.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the methods for upserting rows.
+package gdoctableapp
+
+import (
+	"fmt"
+)
+
+// upsertRows : Update the rows matched by the key column, and append the unmatched rows.
+// Only the cells whose values are changed are rewritten.
+func (o *obj) upsertRows() error {
+	u := o.params.UpsertRowsRequest
+	if u == nil || len(u.Values) == 0 {
+		return fmt.Errorf("Values for upserting are not set")
+	}
+	table := o.docTable.Table
+	if u.KeyColumn < 0 || u.KeyColumn >= table.Columns {
+		return fmt.Errorf("KeyColumn %d is outside of the table", u.KeyColumn)
+	}
+	if u.HeaderRows < 0 || u.HeaderRows > table.Rows {
+		return fmt.Errorf("HeaderRows must be from 0 to the rows of the table")
+	}
+	values, err := o.getValues()
+	if err != nil {
+		return err
+	}
+	rows := map[string]int64{}
+	for i := int64(len(values)) - 1; i >= u.HeaderRows; i-- {
+		if u.KeyColumn < int64(len(values[i])) {
+			rows[values[i][u.KeyColumn]] = i
+		}
+	}
+	res := &UpsertResult{}
+	keys := map[string]bool{}
+	var vos []ValueObject
+	next := table.Rows
+	for n, row := range u.Values {
+		if u.KeyColumn >= int64(len(row)) {
+			return fmt.Errorf("Key of the row %d of values is not set", n)
+		}
+		key, _, _, err := o.parseValue(row[u.KeyColumn], u.KeyColumn, nil)
+		if err != nil {
+			return err
+		}
+		if keys[key] {
+			return fmt.Errorf("Key %q is duplicated in the values", key)
+		}
+		keys[key] = true
+		i, ok := rows[key]
+		if !ok {
+			vo := ValueObject{Values: [][]interface{}{row}}
+			vo.Range.StartRowIndex = next
+			vos = append(vos, vo)
+			next++
+			res.Inserted++
+			continue
+		}
+		changed := false
+		for j, v := range row {
+			s, _, _, err := o.parseValue(v, int64(j), nil)
+			if err != nil {
+				return err
+			}
+			if j < len(values[i]) && s == values[i][j] {
+				continue
+			}
+			vo := ValueObject{Values: [][]interface{}{{v}}}
+			vo.Range.StartRowIndex = i
+			vo.Range.StartColumnIndex = int64(j)
+			vos = append(vos, vo)
+			changed = true
+		}
+		if changed {
+			res.Updated++
+		} else {
+			res.Unchanged++
+		}
+	}
+	o.result.UpsertResult = res
+	if len(vos) == 0 {
+		return nil
+	}
+	o.params.ValuesObject = vos
+	if err := o.setValuesMain(); err != nil {
+		return err
+	}
+	return nil
+}
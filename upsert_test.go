@@ -0,0 +1,33 @@
+package gdoctableapp
+
+import (
+	"testing"
+)
+
+func TestUpsertRows(t *testing.T) {
+	f := newTestTable(t, [][]interface{}{{"id", "name"}, {"1", "a"}, {"2", "b"}})
+	u := &UpsertRowsRequest{KeyColumn: 0, HeaderRows: 1, Values: [][]interface{}{{"2", "c"}, {"3", "d"}, {"1", "a"}}}
+	res, err := New().Docs("doc").SetBackend(f).UpsertRows(u).Do(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := *res.UpsertResult, (UpsertResult{Updated: 1, Inserted: 1, Unchanged: 1}); got != want {
+		t.Errorf("result = %+v, want %+v", got, want)
+	}
+	assertValues(t, testValues(t, f), [][]string{{"id", "name"}, {"1", "a"}, {"2", "c"}, {"3", "d"}})
+}
+
+func TestUpsertRowsKeyColumnOutsideOfTable(t *testing.T) {
+	for _, column := range []int64{-1, 2, 3} {
+		f := newTestTable(t, [][]interface{}{{"1", "a"}, {"2", "b"}})
+		b := &hookBackend{FakeBackend: f}
+		u := &UpsertRowsRequest{KeyColumn: column, Values: [][]interface{}{{"1", "a", "x", "y"}}}
+		if _, err := New().Docs("doc").SetBackend(b).UpsertRows(u).Do(nil); err == nil {
+			t.Errorf("no error for KeyColumn %d", column)
+		}
+		if b.batches != 0 {
+			t.Errorf("batchUpdate was requested %d times for KeyColumn %d, want 0", b.batches, column)
+		}
+		assertValues(t, testValues(t, f), [][]string{{"1", "a"}, {"2", "b"}})
+	}
+}
@@ -0,0 +1,479 @@
+// Package gdoctableapp (values.go) :
+// This is a Golang library for managing tables in Google Document using Google Docs API.
+// This file includes the conversion between the values of table and Go structs.
+package gdoctableapp
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts : Layouts for parsing the values to time.Time when the layout is not set by the tag.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+}
+
+// timeType : Type of time.Time. The embedded time.Time is used as a field without flattening it.
+var timeType = reflect.TypeOf(time.Time{})
+
+// structColumn : Column of the table for a field of struct.
+type structColumn struct {
+	name   string
+	field  string
+	index  []int
+	layout string
+}
+
+// structColumns : Retrieve the columns from the fields of struct.
+// The column name is given by the tag of `gdoc:"Column Name"`. When the tag is not used, the field name is used.
+// The layout of time.Time can be given by the tag of `gdoc:"Column Name,layout=2006-01-02"`. The field with `gdoc:"-"` is skipped.
+// The fields of the embedded structs without the column name are flattened like encoding/json. The field of the shallower
+// depth hides the field of the same column name in the embedded structs. When the same column name is used at the same depth,
+// an error is returned. The columns are in order of the fields, and the fields of the embedded struct are put at its position.
+func structColumns(t reflect.Type) ([]structColumn, error) {
+	type embedded struct {
+		t     reflect.Type
+		index []int
+	}
+	var columns []structColumn
+	depths := map[string]int{}
+	visited := map[reflect.Type]bool{}
+	current := []embedded{{t: t}}
+	for depth := 0; len(current) > 0; depth++ {
+		var next []embedded
+		for _, e := range current {
+			if visited[e.t] {
+				continue
+			}
+			visited[e.t] = true
+			for i := 0; i < e.t.NumField(); i++ {
+				f := e.t.Field(i)
+				tag := f.Tag.Get("gdoc")
+				if tag == "-" {
+					continue
+				}
+				index := append(append([]int{}, e.index...), i)
+				parts := strings.Split(tag, ",")
+				if f.Anonymous && parts[0] == "" {
+					ft := f.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct && ft != timeType {
+						// The pointer of the unexported struct cannot be allocated by UnmarshalValues.
+						if f.PkgPath == "" || f.Type.Kind() != reflect.Ptr {
+							next = append(next, embedded{t: ft, index: index})
+						}
+						continue
+					}
+				}
+				if f.PkgPath != "" {
+					continue
+				}
+				c := structColumn{name: f.Name, field: f.Name, index: index}
+				if parts[0] != "" {
+					c.name = parts[0]
+				}
+				for _, e := range parts[1:] {
+					if strings.HasPrefix(e, "layout=") {
+						c.layout = strings.TrimPrefix(e, "layout=")
+					}
+				}
+				if d, ok := depths[c.name]; ok {
+					if d == depth {
+						return nil, fmt.Errorf("column of %q is duplicated in %s", c.name, t)
+					}
+					continue
+				}
+				depths[c.name] = depth
+				columns = append(columns, c)
+			}
+		}
+		current = next
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		a, b := columns[i].index, columns[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return columns, nil
+}
+
+// fieldByIndex : Return the field of index of v. The pointers of the embedded structs are dereferenced.
+// When alloc is true, the nil pointers are allocated. When alloc is false and the pointer is nil, the zero Value is returned.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// MarshalValues : Create the values of table from records. records is a slice of structs, maps with string keys or pointers of them.
+// The first row of the values is the header row. For structs, the header is given by the tag of `gdoc:"Column Name"`
+// and the order of fields is used as the order of columns. The fields of the embedded structs are flattened, and the cells
+// of the nil pointer of the embedded struct are nil. For maps, the keys of all maps are used, and they are sorted in
+// ascending order of bytes like sort.Strings. So the order of columns does not depend on the iteration order of maps.
+// When columns are given, only the columns are used in the order of columns.
+// The values are returned without converting to strings, so the formatters set by SetFormatter are used for them.
+// Only time.Time with the tag of layout is converted to string with the layout.
+func MarshalValues(records interface{}, columns ...string) ([][]interface{}, error) {
+	rv := reflect.ValueOf(records)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("records must be a slice of structs or maps")
+	}
+	elemType := rv.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	switch elemType.Kind() {
+	case reflect.Struct:
+		return marshalStructs(rv, elemType, columns)
+	case reflect.Map:
+		if elemType.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("keys of maps must be string")
+		}
+		return marshalMaps(rv, columns)
+	}
+	return nil, fmt.Errorf("records must be a slice of structs or maps")
+}
+
+// marshalStructs : Create the values of table from a slice of structs.
+func marshalStructs(rv reflect.Value, elemType reflect.Type, names []string) ([][]interface{}, error) {
+	columns, err := structColumns(elemType)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) > 0 {
+		var selected []structColumn
+		for _, name := range names {
+			found := false
+			for _, c := range columns {
+				if c.name == name {
+					selected = append(selected, c)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("column of %q was not found in %s", name, elemType)
+			}
+		}
+		columns = selected
+	}
+	header := []interface{}{}
+	for _, c := range columns {
+		header = append(header, c.name)
+	}
+	values := [][]interface{}{header}
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				values = append(values, make([]interface{}, len(columns)))
+				continue
+			}
+			elem = elem.Elem()
+		}
+		row := []interface{}{}
+		for _, c := range columns {
+			row = append(row, fieldValue(fieldByIndex(elem, c.index, false), c.layout))
+		}
+		values = append(values, row)
+	}
+	return values, nil
+}
+
+// fieldValue : Return the value of the field for the cell. The field in the nil embedded struct is nil.
+func fieldValue(v reflect.Value, layout string) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	value := v.Interface()
+	if t, ok := value.(time.Time); ok && layout != "" {
+		return t.Format(layout)
+	}
+	return value
+}
+
+// marshalMaps : Create the values of table from a slice of maps.
+func marshalMaps(rv reflect.Value, columns []string) ([][]interface{}, error) {
+	if len(columns) == 0 {
+		keys := map[string]bool{}
+		for i := 0; i < rv.Len(); i++ {
+			m := mapElem(rv, i)
+			if !m.IsValid() {
+				continue
+			}
+			for _, k := range m.MapKeys() {
+				if !keys[k.String()] {
+					keys[k.String()] = true
+					columns = append(columns, k.String())
+				}
+			}
+		}
+		sort.Strings(columns)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("columns were not found in records")
+	}
+	header := []interface{}{}
+	for _, c := range columns {
+		header = append(header, c)
+	}
+	values := [][]interface{}{header}
+	for i := 0; i < rv.Len(); i++ {
+		m := mapElem(rv, i)
+		if !m.IsValid() {
+			values = append(values, make([]interface{}, len(columns)))
+			continue
+		}
+		row := []interface{}{}
+		for _, c := range columns {
+			v := m.MapIndex(reflect.ValueOf(c).Convert(m.Type().Key()))
+			if !v.IsValid() {
+				row = append(row, nil)
+				continue
+			}
+			row = append(row, v.Interface())
+		}
+		values = append(values, row)
+	}
+	return values, nil
+}
+
+// mapElem : Return the map of the index i of rv. The pointer is dereferenced. When the pointer is nil, the zero Value is returned.
+func mapElem(rv reflect.Value, i int) reflect.Value {
+	m := rv.Index(i)
+	if m.Kind() == reflect.Ptr {
+		if m.IsNil() {
+			return reflect.Value{}
+		}
+		m = m.Elem()
+	}
+	return m
+}
+
+// NewCreateTableRequest : Create CreateTableRequest from records. The header row and the rows of records are used as the values.
+// Please set Index or Append of the returned object. About records and columns, please see MarshalValues.
+func NewCreateTableRequest(records interface{}, columns ...string) (*CreateTableRequest, error) {
+	values, err := MarshalValues(records, columns...)
+	if err != nil {
+		return nil, err
+	}
+	return &CreateTableRequest{
+		Rows:    int64(len(values)),
+		Columns: int64(len(values[0])),
+		Values:  values,
+	}, nil
+}
+
+// NewAppendRowRequest : Create AppendRowRequest from records. The header row is not included.
+// The order of columns is required to be the same as the table. About records and columns, please see MarshalValues.
+func NewAppendRowRequest(records interface{}, columns ...string) (*AppendRowRequest, error) {
+	values, err := MarshalValues(records, columns...)
+	if err != nil {
+		return nil, err
+	}
+	return &AppendRowRequest{
+		Values: values[1:],
+	}, nil
+}
+
+// UnmarshalValues : Store the values of table into dst. dst is a pointer to a slice of structs.
+// The first row of values is used as the header row, and the header is mapped to the fields by the tag of `gdoc:"Column Name"`.
+// The fields of the embedded structs are flattened, and the nil pointers of the embedded structs are allocated.
+// string, bool, integers, floats, time.Time, pointers of them and encoding.TextUnmarshaler can be used for the fields.
+// When the values of cells cannot be converted, *ValuesError including the row and column of each cell is returned.
+// In this case, the other values are stored into dst. Like encoding/json, the slice of dst is replaced, and the elements of it are not kept.
+func UnmarshalValues(values [][]string, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("dst must be a pointer to a slice of structs")
+	}
+	elemType := rv.Elem().Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("dst must be a pointer to a slice of structs")
+	}
+	if len(values) == 0 {
+		rv.Elem().Set(reflect.MakeSlice(rv.Elem().Type(), 0, 0))
+		return nil
+	}
+	slice := reflect.MakeSlice(rv.Elem().Type(), 0, len(values)-1)
+	header := map[string]int{}
+	for i, e := range values[0] {
+		name := strings.TrimSpace(e)
+		if _, ok := header[name]; !ok {
+			header[name] = i
+		}
+	}
+	columns, err := structColumns(elemType)
+	if err != nil {
+		return err
+	}
+	ve := &ValuesError{}
+	for i, row := range values[1:] {
+		elem := reflect.New(elemType).Elem()
+		for _, c := range columns {
+			col, ok := header[c.name]
+			if !ok || col >= len(row) {
+				continue
+			}
+			if err := setFieldValue(fieldByIndex(elem, c.index, true), row[col], c.layout); err != nil {
+				ve.Errors = append(ve.Errors, CellError{
+					Row:    i + 1,
+					Column: col,
+					Field:  c.field,
+					Value:  row[col],
+					Err:    err,
+				})
+			}
+		}
+		if isPtr {
+			elem = elem.Addr()
+		}
+		slice = reflect.Append(slice, elem)
+	}
+	rv.Elem().Set(slice)
+	if len(ve.Errors) > 0 {
+		return ve
+	}
+	return nil
+}
+
+// setFieldValue : Convert the value of cell and set it to the field.
+func setFieldValue(v reflect.Value, s string, layout string) error {
+	if v.Kind() == reflect.Ptr {
+		if strings.TrimSpace(s) == "" {
+			return nil
+		}
+		p := reflect.New(v.Type().Elem())
+		if err := setFieldValue(p.Elem(), s, layout); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok && v.Type() != timeType {
+			return u.UnmarshalText([]byte(s))
+		}
+	}
+	t := strings.TrimSpace(s)
+	if v.Type() == timeType {
+		if t == "" {
+			return nil
+		}
+		tm, err := parseTime(t, layout)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(tm))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(s))
+			return nil
+		}
+	case reflect.Bool:
+		if t == "" {
+			v.SetBool(false)
+			return nil
+		}
+		b, err := strconv.ParseBool(t)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if t == "" {
+			v.SetInt(0)
+			return nil
+		}
+		n, err := strconv.ParseInt(removeThousandsSeparator(t), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if t == "" {
+			v.SetUint(0)
+			return nil
+		}
+		n, err := strconv.ParseUint(removeThousandsSeparator(t), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if t == "" {
+			v.SetFloat(0)
+			return nil
+		}
+		f, err := strconv.ParseFloat(removeThousandsSeparator(t), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+		return nil
+	}
+	return fmt.Errorf("type of %s cannot be used", v.Type())
+}
+
+// removeThousandsSeparator : Remove the thousands separators of "," from the number.
+func removeThousandsSeparator(s string) string {
+	return strings.Replace(s, ",", "", -1)
+}
+
+// parseTime : Parse the value to time.Time with layout. When layout is empty, timeLayouts are used.
+func parseTime(s, layout string) (time.Time, error) {
+	if layout != "" {
+		return time.Parse(layout, s)
+	}
+	for _, l := range timeLayouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q cannot be parsed as time", s)
+}
@@ -0,0 +1,365 @@
+package gdoctableapp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshalValuesMaps(t *testing.T) {
+	m1 := map[string]interface{}{"Name": "a", "Age": 1}
+	m2 := map[string]interface{}{"Name": "b", "Note": "x"}
+	want := [][]interface{}{
+		{"Age", "Name", "Note"},
+		{1, "a", nil},
+		{nil, "b", "x"},
+	}
+	tests := []struct {
+		name    string
+		records interface{}
+		want    [][]interface{}
+	}{
+		{"maps", []map[string]interface{}{m1, m2}, want},
+		{"pointers of maps", []*map[string]interface{}{&m1, &m2}, want},
+		{"nil pointer of map", []*map[string]interface{}{&m1, nil, &m2}, [][]interface{}{want[0], want[1], {nil, nil, nil}, want[2]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalValues(tt.records)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("values = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalValuesInvalidRecords(t *testing.T) {
+	for _, records := range []interface{}{
+		"a",
+		[]int{1},
+		[]map[int]string{{1: "a"}},
+		[]*map[int]string{{1: "a"}},
+	} {
+		if _, err := MarshalValues(records); err == nil {
+			t.Errorf("error was not returned for %T", records)
+		}
+	}
+}
+
+func TestUnmarshalValuesReplacesSlice(t *testing.T) {
+	type row struct {
+		Name string
+		Age  int
+	}
+	rows := []row{{Name: "old", Age: 9}}
+	if err := UnmarshalValues([][]string{{"Name", "Age"}, {"a", "1"}, {"b", "2"}}, &rows); err != nil {
+		t.Fatal(err)
+	}
+	if want := []row{{"a", 1}, {"b", 2}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+	if err := UnmarshalValues([][]string{{"Name", "Age"}, {"c", "3"}}, &rows); err != nil {
+		t.Fatal(err)
+	}
+	if want := []row{{"c", 3}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+	if err := UnmarshalValues(nil, &rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows == nil || len(rows) != 0 {
+		t.Errorf("rows = %v, want empty slice", rows)
+	}
+}
+
+// testUpper : Type implementing encoding.TextUnmarshaler. The text is converted to upper case.
+type testUpper string
+
+func (u *testUpper) UnmarshalText(text []byte) error {
+	*u = testUpper(strings.ToUpper(string(text)))
+	return nil
+}
+
+func TestUnmarshalValues(t *testing.T) {
+	type row struct {
+		ID      int       `gdoc:"ID"`
+		Name    string    `gdoc:"Full Name"`
+		Price   float64   `gdoc:"Price"`
+		Done    bool      `gdoc:"Done"`
+		Created time.Time `gdoc:"Created,layout=2006/01/02"`
+		Updated time.Time `gdoc:"Updated"`
+		Count   *int      `gdoc:"Count"`
+		Due     *time.Time
+		Code    testUpper
+		Memo    string `gdoc:"-"`
+		Missing string
+		hidden  string
+	}
+	values := [][]string{
+		{"ID", " Full Name ", "Price", "Done", "Created", "Updated", "Count", "Due", "Code", "Memo", "hidden", "Extra"},
+		{"1", "a b ", "1,234.5", "true", "2023/01/02", "2023-01-02T03:04:05Z", "3", "2023-01-03", "ab", "x", "y", "z"},
+		{" 2 ", "", "", "", "", "", "", " ", "", "", "", ""},
+	}
+	var rows []row
+	if err := UnmarshalValues(values, &rows); err != nil {
+		t.Fatal(err)
+	}
+	count := 3
+	due := time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)
+	want := []row{
+		{
+			ID: 1, Name: "a b ", Price: 1234.5, Done: true,
+			Created: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+			Updated: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+			Count:   &count, Due: &due, Code: "AB",
+		},
+		// The empty cells are the zero values, and the pointers of the empty cells are nil.
+		{ID: 2},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+
+	// The pointers of structs can be used, and the short row is the zero value for the missing cells.
+	var ptrs []*row
+	if err := UnmarshalValues([][]string{{"Full Name", "ID"}, {"c"}}, &ptrs); err != nil {
+		t.Fatal(err)
+	}
+	if len(ptrs) != 1 || !reflect.DeepEqual(*ptrs[0], row{Name: "c"}) {
+		t.Errorf("rows = %+v, want [{Name: c}]", ptrs)
+	}
+}
+
+func TestUnmarshalValuesErrors(t *testing.T) {
+	type row struct {
+		ID      int     `gdoc:"ID"`
+		Price   float32 `gdoc:"Price"`
+		Small   int8
+		Created time.Time `gdoc:"Created,layout=2006-01-02"`
+		Name    string
+	}
+	values := [][]string{
+		{"Name", "ID", "Price", "Small", "Created"},
+		{"a", "x", "1", "1", "2023-01-02"},
+		{"b", "2", "1e50", "128", "2023/01/02"},
+	}
+	var rows []row
+	err := UnmarshalValues(values, &rows)
+	var ve *ValuesError
+	if !errors.As(err, &ve) {
+		t.Fatalf("err = %v, want *ValuesError", err)
+	}
+	var got []string
+	for _, e := range ve.Errors {
+		got = append(got, fmt.Sprintf("%d,%d %s %q", e.Row, e.Column, e.Field, e.Value))
+	}
+	want := []string{`1,1 ID "x"`, `2,2 Price "1e50"`, `2,3 Small "128"`, `2,4 Created "2023/01/02"`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("errors = %q, want %q", got, want)
+	}
+	var ne *strconv.NumError
+	if !errors.As(ve.Errors[0], &ne) {
+		t.Errorf("error of the cell = %v, want *strconv.NumError", ve.Errors[0].Err)
+	}
+	// The other values are stored.
+	if want := []row{{Price: 1, Small: 1, Created: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), Name: "a"}, {ID: 2, Name: "b"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+
+	for _, dst := range []interface{}{rows, &[]int{}, new(row), nil} {
+		if err := UnmarshalValues(values, dst); err == nil {
+			t.Errorf("no error for %T", dst)
+		}
+	}
+	var unsupported []struct{ Tags []string }
+	if err := UnmarshalValues([][]string{{"Tags"}, {"a"}}, &unsupported); err == nil {
+		t.Error("no error for the unsupported type")
+	}
+}
+
+type testBase struct {
+	ID      int `gdoc:"ID"`
+	Created time.Time
+}
+
+// Audit : Exported struct for embedding by the pointer.
+type Audit struct {
+	Author string
+	Name   string `gdoc:"Audit Name"`
+}
+
+type testInternal struct {
+	Internal string
+}
+
+type testAuthor struct {
+	Author string
+}
+
+func TestUnmarshalValuesWithEmbeddedStructs(t *testing.T) {
+	type row struct {
+		testBase
+		Name string
+		*Audit
+		ID2 int `gdoc:"ID"` // This hides ID of testBase.
+		testInternal
+		*testAuthor // The pointer of the unexported struct is not used.
+	}
+	values := [][]string{
+		{"ID", "Created", "Name", "Author", "Audit Name", "Internal"},
+		{"1", "2023-01-02", "a", "x", "y", "z"},
+	}
+	var rows []row
+	if err := UnmarshalValues(values, &rows); err != nil {
+		t.Fatal(err)
+	}
+	want := []row{{
+		testBase:     testBase{Created: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Name:         "a",
+		Audit:        &Audit{Author: "x", Name: "y"},
+		ID2:          1,
+		testInternal: testInternal{Internal: "z"},
+	}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+
+	for _, dst := range []interface{}{
+		&[]struct {
+			ID   string `gdoc:"Name"`
+			Name string
+		}{},
+		&[]struct {
+			Audit
+			testAuthor
+		}{},
+	} {
+		if err := UnmarshalValues(values, dst); err == nil || !strings.Contains(err.Error(), "is duplicated") {
+			t.Errorf("err = %v, want the error of the duplicated column for %T", err, dst)
+		}
+	}
+}
+
+type testProduct struct {
+	Name    string    `gdoc:"Product Name"`
+	ID      int       `gdoc:"ID"`
+	Price   *float64  `gdoc:"Price"`
+	Created time.Time `gdoc:"Created,layout=2006-01-02"`
+	Memo    string    `gdoc:"-"`
+	*Audit
+	internal string
+}
+
+func TestMarshalValuesStructs(t *testing.T) {
+	price := 1.5
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	p1 := testProduct{Name: "a", ID: 1, Price: &price, Created: created, Memo: "x", Audit: &Audit{Author: "u", Name: "v"}, internal: "y"}
+	p2 := testProduct{Name: "b", ID: 2}
+	header := []interface{}{"Product Name", "ID", "Price", "Created", "Author", "Audit Name"}
+	// The nil pointer of the field and the embedded struct is nil, and time.Time with the layout is converted to string.
+	row1 := []interface{}{"a", 1, 1.5, "2023-01-02", "u", "v"}
+	row2 := []interface{}{"b", 2, nil, "0001-01-01", nil, nil}
+	tests := []struct {
+		name    string
+		records interface{}
+		columns []string
+		want    [][]interface{}
+	}{
+		{"structs", []testProduct{p1, p2}, nil, [][]interface{}{header, row1, row2}},
+		{"pointers of structs", []*testProduct{&p1, nil, &p2}, nil, [][]interface{}{header, row1, make([]interface{}, 6), row2}},
+		{"array", [1]testProduct{p2}, nil, [][]interface{}{header, row2}},
+		{"columns", []testProduct{p1}, []string{"ID", "Author"}, [][]interface{}{{"ID", "Author"}, {1, "u"}}},
+		{"no records", []testProduct{}, nil, [][]interface{}{header}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalValues(tt.records, tt.columns...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("values = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	for _, columns := range [][]string{{"Memo"}, {"internal"}, {"Name"}} {
+		if _, err := MarshalValues([]testProduct{p1}, columns...); err == nil {
+			t.Errorf("no error for %q", columns)
+		}
+	}
+}
+
+func TestMarshalValuesMapsInSortedOrder(t *testing.T) {
+	m := map[string]int{}
+	for i, k := range []string{"b", "B", "a", "10", "2", "_", "Name", "name", "日本"} {
+		m[k] = i
+	}
+	// The keys of all maps are sorted in ascending order of bytes. The numeric keys are not sorted as numbers.
+	want := []interface{}{"10", "2", "B", "Name", "_", "a", "b", "name", "z", "日本"}
+	for i := 0; i < 20; i++ {
+		got, err := MarshalValues([]map[string]int{m, {"z": 10}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got[0], want) {
+			t.Fatalf("header = %q, want %q", got[0], want)
+		}
+		if got[1][0] != 3 || got[1][8] != nil || got[2][8] != 10 || got[2][0] != nil {
+			t.Fatalf("values = %v", got[1:])
+		}
+	}
+}
+
+func TestNewCreateTableRequestAndAppendRowRequest(t *testing.T) {
+	price := 1.5
+	products := []*testProduct{
+		{Name: "a", ID: 1, Price: &price, Created: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), Audit: &Audit{Author: "u"}},
+		{Name: "b", ID: 2, Memo: "x"},
+	}
+	c, err := NewCreateTableRequest(products, "ID", "Product Name", "Price", "Created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Rows != 3 || c.Columns != 4 {
+		t.Errorf("rows and columns = %d, %d, want 3, 4", c.Rows, c.Columns)
+	}
+	f := NewFakeBackend()
+	f.NewDocument("doc")
+	c.Index = 1
+	if _, err := New().Docs("doc").SetBackend(f).SetColumnFormatter(2, NumberFormatter(2, ",")).CreateTable(c).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewAppendRowRequest([]testProduct{{Name: "c", ID: 3, Price: &price}}, "ID", "Product Name", "Price", "Created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Values) != 1 {
+		t.Fatalf("values = %v, want 1 row without the header row", a.Values)
+	}
+	if _, err := New().Docs("doc").SetBackend(f).AppendRow(a).Do(nil); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, testValues(t, f), [][]string{
+		{"ID", "Product Name", "Price", "Created"},
+		{"1", "a", "1.50", "2023-01-02"},
+		{"2", "b", "", "0001-01-01"},
+		{"3", "c", "1.5", "0001-01-01"},
+	})
+	// The table of only the header row is created from no structs, while the columns cannot be found from no maps.
+	if c, err := NewCreateTableRequest([]testProduct{}); err != nil || c.Rows != 1 || c.Columns != 6 {
+		t.Errorf("request = %+v, %v, want 1 row and 6 columns", c, err)
+	}
+	if _, err := NewCreateTableRequest([]map[string]int{}); err == nil {
+		t.Error("no error for no maps")
+	}
+	if _, err := NewAppendRowRequest([]testProduct{}, "Memo"); err == nil {
+		t.Error("no error for the skipped field")
+	}
+}